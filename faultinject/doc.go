@@ -0,0 +1,3 @@
+// Package faultinject provides middlewares for injecting faults, such as latency, aborted calls or dropped calls, into
+// gRPC clients and servers for chaos testing.
+package faultinject
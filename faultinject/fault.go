@@ -0,0 +1,97 @@
+package faultinject
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fault describes the faults injected into a call. The drop is evaluated first, then the delay, then the abort.
+type Fault struct {
+	// Delay adds latency to the call.
+	Delay *Delay
+	// Abort fails the call with a status code.
+	Abort *Abort
+	// Drop drops the call: it never reaches the invoker or the handler and waits until the context ends.
+	Drop *Drop
+}
+
+// Delay adds latency to a call.
+type Delay struct {
+	// Fixed is the fixed latency.
+	Fixed time.Duration
+	// Jitter is the maximum random latency that is added to the fixed latency.
+	Jitter time.Duration
+	// Percentage of the calls, between 0 and 100, that are delayed. A zero percentage means none.
+	Percentage float64
+}
+
+// Abort fails a call with a status code.
+type Abort struct {
+	// Code is the status code of the error.
+	Code codes.Code
+	// Message is the message of the error.
+	Message string
+	// Percentage of the calls, between 0 and 100, that are aborted. A zero percentage means none.
+	Percentage float64
+}
+
+// Drop drops a call.
+type Drop struct {
+	// Percentage of the calls, between 0 and 100, that are dropped. A zero percentage means none.
+	Percentage float64
+}
+
+func (f Fault) inject(ctx context.Context, random func() float64) error {
+	if f.Drop != nil && hit(f.Drop.Percentage, random) {
+		<-ctx.Done()
+
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	if f.Delay != nil && hit(f.Delay.Percentage, random) {
+		delay := f.Delay.Fixed
+
+		if f.Delay.Jitter > 0 {
+			delay += time.Duration(random() * float64(f.Delay.Jitter))
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	if f.Abort != nil && hit(f.Abort.Percentage, random) {
+		msg := f.Abort.Message
+		if msg == "" {
+			msg = "aborted by fault injection"
+		}
+
+		return status.Error(f.Abort.Code, msg)
+	}
+
+	return nil
+}
+
+func hit(percentage float64, random func() float64) bool {
+	return percentage > 0 && random()*100 < percentage
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+
+	case <-t.C:
+		return nil
+	}
+}
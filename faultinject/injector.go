@@ -0,0 +1,262 @@
+package faultinject
+
+import (
+	"context"
+	"math/rand/v2"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataDelay is the incoming metadata key for delaying a call, e.g. "x-fault-delay: 100ms".
+	MetadataDelay = "x-fault-delay"
+	// MetadataAbort is the incoming metadata key for aborting a call with a status code, e.g. "x-fault-abort: 14" or
+	// "x-fault-abort: UNAVAILABLE".
+	MetadataAbort = "x-fault-abort"
+	// MetadataDrop is the incoming metadata key for dropping a call, e.g. "x-fault-drop: true".
+	MetadataDrop = "x-fault-drop"
+)
+
+type (
+	skipFaultCtxKey struct{}
+	faultCtxKey     struct{}
+)
+
+// Option configures an Injector.
+type Option func(i *Injector)
+
+// Injector decides which faults are injected into a call.
+//
+// The fault of a call is resolved in this order:
+//   - the fault in the context, see WithFault.
+//   - the fault in the incoming metadata, if enabled with WithMetadata, only for the incoming calls.
+//   - the fault of the full method name, e.g. "/pkg.Service/Method".
+//   - the fault of the first pattern that matches the full method name, in the order they are added.
+//   - the fault of the service, e.g. "pkg.Service".
+//   - the default fault.
+type Injector struct {
+	enabled atomic.Bool
+
+	methods      map[string]Fault
+	services     map[string]Fault
+	patterns     []patternFault
+	defaultFault *Fault
+	metadata     bool
+	random       func() float64
+}
+
+type patternFault struct {
+	pattern string
+	fault   Fault
+}
+
+// New creates a new enabled injector.
+func New(opts ...Option) *Injector {
+	i := &Injector{
+		methods:  make(map[string]Fault),
+		services: make(map[string]Fault),
+		random:   rand.Float64, //nolint: gosec
+	}
+
+	i.enabled.Store(true)
+
+	for _, o := range opts {
+		o(i)
+	}
+
+	return i
+}
+
+// WithMethodFault sets the fault for a full method name, e.g. "/pkg.Service/Method".
+func WithMethodFault(fullMethod string, f Fault) Option {
+	return func(i *Injector) {
+		i.methods[fullMethod] = f
+	}
+}
+
+// WithServiceFault sets the fault for all the methods of a service, e.g. "pkg.Service".
+func WithServiceFault(service string, f Fault) Option {
+	return func(i *Injector) {
+		i.services[service] = f
+	}
+}
+
+// WithPatternFault sets the fault for all the methods whose full name matches the glob pattern, e.g.
+// "/pkg.Service/*". See path.Match for the pattern syntax. A malformed pattern never matches.
+func WithPatternFault(pattern string, f Fault) Option {
+	return func(i *Injector) {
+		i.patterns = append(i.patterns, patternFault{pattern: pattern, fault: f})
+	}
+}
+
+// WithDefaultFault sets the fault for the methods that do not match any rule.
+func WithDefaultFault(f Fault) Option {
+	return func(i *Injector) {
+		i.defaultFault = &f
+	}
+}
+
+// WithMetadata enables the faults from the incoming metadata, see MetadataDelay, MetadataAbort and MetadataDrop. The
+// faults apply to every call, and only to the incoming calls: the client interceptors ignore them, so the metadata of a
+// call does not fault the downstream calls made while handling it. Only enable it when the callers are trusted.
+func WithMetadata() Option {
+	return func(i *Injector) {
+		i.metadata = true
+	}
+}
+
+// WithRand sets the random number generator, it returns a number in [0, 1). Useful for deterministic tests.
+func WithRand(f func() float64) Option {
+	return func(i *Injector) {
+		i.random = f
+	}
+}
+
+// Enable enables the injector.
+func (i *Injector) Enable() {
+	i.enabled.Store(true)
+}
+
+// Disable disables the injector, no fault is injected until it is enabled again.
+func (i *Injector) Disable() {
+	i.enabled.Store(false)
+}
+
+// Enabled checks whether the injector is enabled.
+func (i *Injector) Enabled() bool {
+	return i.enabled.Load()
+}
+
+// Inject injects the fault of the method into an incoming call. It returns the error of the call if the call is aborted
+// or dropped.
+func (i *Injector) Inject(ctx context.Context, fullMethod string) error {
+	return i.inject(ctx, fullMethod, true)
+}
+
+// injectOutgoing injects the fault of the method into an outgoing call, the faults of the incoming metadata are ignored.
+func (i *Injector) injectOutgoing(ctx context.Context, fullMethod string) error {
+	return i.inject(ctx, fullMethod, false)
+}
+
+func (i *Injector) inject(ctx context.Context, fullMethod string, incoming bool) error {
+	f, ok := i.fault(ctx, fullMethod, incoming)
+	if !ok {
+		return nil
+	}
+
+	return f.inject(ctx, i.random)
+}
+
+func (i *Injector) fault(ctx context.Context, fullMethod string, incoming bool) (Fault, bool) {
+	if !i.Enabled() || IsFaultSkipped(ctx) {
+		return Fault{}, false
+	}
+
+	if f, ok := ctx.Value(faultCtxKey{}).(Fault); ok {
+		return f, true
+	}
+
+	if i.metadata && incoming {
+		if f, ok := faultFromMetadata(ctx); ok {
+			return f, true
+		}
+	}
+
+	if f, ok := i.methods[fullMethod]; ok {
+		return f, true
+	}
+
+	for _, pf := range i.patterns {
+		if ok, err := path.Match(pf.pattern, fullMethod); err == nil && ok {
+			return pf.fault, true
+		}
+	}
+
+	if f, ok := i.services[serviceName(fullMethod)]; ok {
+		return f, true
+	}
+
+	if i.defaultFault != nil {
+		return *i.defaultFault, true
+	}
+
+	return Fault{}, false
+}
+
+// WithFault injects the fault into the calls made or handled with the context, regardless of the rules of the injector.
+func WithFault(ctx context.Context, f Fault) context.Context {
+	return context.WithValue(ctx, faultCtxKey{}, f)
+}
+
+// SkipFault skips the fault injection.
+func SkipFault(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipFaultCtxKey{}, true)
+}
+
+// IsFaultSkipped checks whether the fault injection is bypassed.
+func IsFaultSkipped(ctx context.Context) bool {
+	skipped, found := ctx.Value(skipFaultCtxKey{}).(bool)
+
+	return found && skipped
+}
+
+func faultFromMetadata(ctx context.Context) (Fault, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Fault{}, false
+	}
+
+	var (
+		f     Fault
+		found bool
+	)
+
+	if v := md.Get(MetadataDelay); len(v) > 0 {
+		if d, err := time.ParseDuration(v[0]); err == nil {
+			f.Delay = &Delay{Fixed: d, Percentage: 100}
+			found = true
+		}
+	}
+
+	if v := md.Get(MetadataAbort); len(v) > 0 {
+		if c, ok := parseCode(v[0]); ok {
+			f.Abort = &Abort{Code: c, Percentage: 100}
+			found = true
+		}
+	}
+
+	if v := md.Get(MetadataDrop); len(v) > 0 {
+		if drop, err := strconv.ParseBool(v[0]); err == nil && drop {
+			f.Drop = &Drop{Percentage: 100}
+			found = true
+		}
+	}
+
+	return f, found
+}
+
+func parseCode(s string) (codes.Code, bool) {
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return codes.Code(n), true
+	}
+
+	var c codes.Code
+
+	if err := c.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(s)))); err != nil {
+		return 0, false
+	}
+
+	return c, true
+}
+
+func serviceName(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return service
+}
@@ -0,0 +1,242 @@
+package faultinject_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/faultinject"
+)
+
+func abort(code codes.Code) faultinject.Fault {
+	return faultinject.Fault{Abort: &faultinject.Abort{Code: code, Percentage: 100}}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(
+		faultinject.WithMethodFault("/pkg.Search/Search", abort(codes.NotFound)),
+		faultinject.WithPatternFault("/pkg.Export/Export*", abort(codes.Unavailable)),
+		faultinject.WithServiceFault("pkg.Export", abort(codes.Internal)),
+		faultinject.WithDefaultFault(abort(codes.Aborted)),
+	)
+
+	testCases := []struct {
+		scenario     string
+		context      context.Context
+		method       string
+		expectedCode codes.Code
+	}{
+		{
+			scenario:     "method",
+			context:      context.Background(),
+			method:       "/pkg.Search/Search",
+			expectedCode: codes.NotFound,
+		},
+		{
+			scenario:     "pattern",
+			context:      context.Background(),
+			method:       "/pkg.Export/ExportAll",
+			expectedCode: codes.Unavailable,
+		},
+		{
+			scenario:     "service",
+			context:      context.Background(),
+			method:       "/pkg.Export/Status",
+			expectedCode: codes.Internal,
+		},
+		{
+			scenario:     "default",
+			context:      context.Background(),
+			method:       "/pkg.Other/Method",
+			expectedCode: codes.Aborted,
+		},
+		{
+			scenario:     "context",
+			context:      faultinject.WithFault(context.Background(), abort(codes.DataLoss)),
+			method:       "/pkg.Search/Search",
+			expectedCode: codes.DataLoss,
+		},
+		{
+			scenario:     "metadata is not enabled",
+			context:      metadata.NewIncomingContext(context.Background(), metadata.Pairs(faultinject.MetadataAbort, "14")),
+			method:       "/pkg.Search/Search",
+			expectedCode: codes.NotFound,
+		},
+		{
+			scenario:     "skipped",
+			context:      faultinject.SkipFault(context.Background()),
+			method:       "/pkg.Search/Search",
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			err := injector.Inject(tc.context, tc.method)
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestInjector_EnableDisable(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithDefaultFault(abort(codes.Unavailable)))
+
+	assert.True(t, injector.Enabled())
+	assert.Equal(t, codes.Unavailable, status.Code(injector.Inject(context.Background(), "/pkg.Service/Method")))
+
+	injector.Disable()
+
+	assert.False(t, injector.Enabled())
+	assert.NoError(t, injector.Inject(context.Background(), "/pkg.Service/Method"))
+
+	injector.Enable()
+
+	assert.Equal(t, codes.Unavailable, status.Code(injector.Inject(context.Background(), "/pkg.Service/Method")))
+}
+
+func TestInjector_Metadata(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithMetadata())
+
+	testCases := []struct {
+		scenario     string
+		metadata     metadata.MD
+		expectedCode codes.Code
+	}{
+		{
+			scenario:     "no metadata",
+			expectedCode: codes.OK,
+		},
+		{
+			scenario:     "abort with number",
+			metadata:     metadata.Pairs(faultinject.MetadataAbort, "14"),
+			expectedCode: codes.Unavailable,
+		},
+		{
+			scenario:     "abort with name",
+			metadata:     metadata.Pairs(faultinject.MetadataAbort, "resource_exhausted"),
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			scenario:     "malformed abort",
+			metadata:     metadata.Pairs(faultinject.MetadataAbort, "bogus"),
+			expectedCode: codes.OK,
+		},
+		{
+			scenario:     "delay",
+			metadata:     metadata.Pairs(faultinject.MetadataDelay, "1h"),
+			expectedCode: codes.DeadlineExceeded,
+		},
+		{
+			scenario:     "drop",
+			metadata:     metadata.Pairs(faultinject.MetadataDrop, "true"),
+			expectedCode: codes.DeadlineExceeded,
+		},
+		{
+			scenario:     "no drop",
+			metadata:     metadata.Pairs(faultinject.MetadataDrop, "false"),
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			if tc.metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, tc.metadata)
+			}
+
+			err := injector.Inject(ctx, "/pkg.Service/Method")
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestInjector_Percentage(t *testing.T) {
+	t.Parallel()
+
+	random := 0.5
+
+	injector := faultinject.New(
+		faultinject.WithRand(func() float64 {
+			return random
+		}),
+		faultinject.WithDefaultFault(faultinject.Fault{
+			Abort: &faultinject.Abort{Code: codes.Unavailable, Message: "chaos", Percentage: 30},
+		}),
+	)
+
+	assert.NoError(t, injector.Inject(context.Background(), "/pkg.Service/Method"))
+
+	random = 0.2
+
+	assert.EqualError(t, injector.Inject(context.Background(), "/pkg.Service/Method"), "rpc error: code = Unavailable desc = chaos")
+}
+
+func TestInjector_Delay(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(
+		faultinject.WithRand(func() float64 {
+			return 0.5
+		}),
+		faultinject.WithDefaultFault(faultinject.Fault{
+			Delay: &faultinject.Delay{Fixed: 20 * time.Millisecond, Jitter: 40 * time.Millisecond, Percentage: 100},
+			Abort: &faultinject.Abort{Code: codes.Unavailable, Percentage: 100},
+		}),
+	)
+
+	start := time.Now()
+	err := injector.Inject(context.Background(), "/pkg.Service/Method")
+
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.EqualError(t, err, "rpc error: code = Unavailable desc = aborted by fault injection")
+}
+
+func TestInjector_DelayIsCanceled(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithDefaultFault(faultinject.Fault{
+		Delay: &faultinject.Delay{Fixed: time.Hour, Percentage: 100},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	err := injector.Inject(ctx, "/pkg.Service/Method")
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestInjector_ZeroPercentage(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithDefaultFault(faultinject.Fault{
+		Delay: &faultinject.Delay{Fixed: time.Hour},
+		Abort: &faultinject.Abort{Code: codes.Unavailable},
+		Drop:  &faultinject.Drop{},
+	}))
+
+	assert.NoError(t, injector.Inject(context.Background(), "/pkg.Service/Method"), "a zero percentage injects no fault")
+}
@@ -0,0 +1,39 @@
+package faultinject
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// StreamClientInterceptor injects the faults of the injector into the outgoing streams.
+func StreamClientInterceptor(injector *Injector) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := injector.injectOutgoing(ctx, method); err != nil {
+			return nil, err
+		}
+
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// StreamServerInterceptor injects the faults of the injector into the incoming streams.
+func StreamServerInterceptor(injector *Injector) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := injector.Inject(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// WithStreamClientInterceptor appends StreamClientInterceptor to dial option.
+func WithStreamClientInterceptor(injector *Injector) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamClientInterceptor(injector))
+}
+
+// WithStreamServerInterceptor appends StreamServerInterceptor to server option.
+func WithStreamServerInterceptor(injector *Injector) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(StreamServerInterceptor(injector))
+}
@@ -0,0 +1,74 @@
+package faultinject_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/faultinject"
+)
+
+func TestStreamClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithMethodFault("/pkg.Service/Fault", abort(codes.Unavailable)))
+	interceptor := faultinject.StreamClientInterceptor(injector)
+
+	var invoked int
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		invoked++
+
+		return nil, nil //nolint: nilnil
+	}
+
+	_, err := interceptor(context.Background(), nil, nil, "/pkg.Service/Fault", streamer)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 0, invoked)
+
+	_, err = interceptor(context.Background(), nil, nil, "/pkg.Service/Method", streamer)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invoked)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithMethodFault("/pkg.Service/Fault", abort(codes.Unavailable)))
+	interceptor := faultinject.StreamServerInterceptor(injector)
+	stream := &serverStream{context: context.Background()}
+
+	var handled int
+
+	handler := func(any, grpc.ServerStream) error {
+		handled++
+
+		return nil
+	}
+
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/pkg.Service/Fault"}, handler)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 0, handled)
+
+	err = interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/pkg.Service/Method"}, handler)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, handled)
+}
+
+type serverStream struct {
+	grpc.ServerStream
+
+	context context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.context
+}
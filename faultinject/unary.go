@@ -0,0 +1,39 @@
+package faultinject
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor injects the faults of the injector into the outgoing calls.
+func UnaryClientInterceptor(injector *Injector) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := injector.injectOutgoing(ctx, method); err != nil {
+			return err
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor injects the faults of the injector into the incoming calls.
+func UnaryServerInterceptor(injector *Injector) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := injector.Inject(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// WithUnaryClientInterceptor appends UnaryClientInterceptor to dial option.
+func WithUnaryClientInterceptor(injector *Injector) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(injector))
+}
+
+// WithUnaryServerInterceptor appends UnaryServerInterceptor to server option.
+func WithUnaryServerInterceptor(injector *Injector) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(UnaryServerInterceptor(injector))
+}
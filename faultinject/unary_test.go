@@ -0,0 +1,82 @@
+package faultinject_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/faultinject"
+)
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithMethodFault("/pkg.Service/Fault", abort(codes.Unavailable)))
+	interceptor := faultinject.UnaryClientInterceptor(injector)
+
+	var invoked int
+
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked++
+
+		return nil
+	}
+
+	err := interceptor(context.Background(), "/pkg.Service/Fault", nil, nil, nil, invoker)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 0, invoked)
+
+	err = interceptor(context.Background(), "/pkg.Service/Method", nil, nil, nil, invoker)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invoked)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	injector := faultinject.New(faultinject.WithMethodFault("/pkg.Service/Fault", abort(codes.Unavailable)))
+	interceptor := faultinject.UnaryServerInterceptor(injector)
+
+	handler := func(context.Context, any) (any, error) {
+		return 42, nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Fault"}, handler)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	resp, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Method"}, handler)
+
+	assert.Equal(t, 42, resp)
+	assert.NoError(t, err)
+}
+
+func TestUnaryClientInterceptor_IgnoresIncomingMetadata(t *testing.T) {
+	t.Parallel()
+
+	interceptor := faultinject.UnaryClientInterceptor(faultinject.New(faultinject.WithMetadata()))
+
+	var invoked int
+
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked++
+
+		return nil
+	}
+
+	// The incoming call asks for a fault, the downstream call is not faulted.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(faultinject.MetadataAbort, "14"))
+
+	err := interceptor(ctx, "/pkg.Service/Method", nil, nil, nil, invoker)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, invoked)
+}
@@ -7,10 +7,12 @@ import (
 
 	"github.com/bool64/ctxd"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 )
 
 func newClientLogger(log ctxd.Logger, opts ...Option) *logger {
 	l := defaultLogger(log)
+	l.kind = "client"
 	l.codeToLevel = DefaultClientCodeToLevel
 
 	for _, o := range opts {
@@ -27,38 +29,96 @@ func UnaryClientInterceptor(logger ctxd.Logger, opts ...Option) grpc.UnaryClient
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		startTime := time.Now()
 
+		ctx = l.propagateDebug(ctx)
 		ctx = clientLoggerContext(ctx, method, startTime)
+		ctx = l.outgoingMetadataContext(ctx)
+		ctx = l.targetContext(ctx, cc)
+
+		l.logStartCall(ctx, "started client unary call")
+
+		stopWatching := l.watchSlowCall(ctx, method, "client unary call is still running", startTime)
+
+		logPayload := l.shouldLogPayloadOf(ctx, method)
+
+		if logPayload {
+			l.logPayload(ctx, "client request payload logged", FieldRequestContent, req)
+		}
+
+		var p *peer.Peer
+
+		if l.peerFields {
+			p = new(peer.Peer)
+			opts = append(opts, grpc.Peer(p))
+		}
+
 		err := invoker(ctx, method, req, reply, cc, opts...)
 
-		duration := time.Since(startTime)
+		stopWatching()
 
-		code := l.errorToCode(err)
-		level := l.codeToLevel(code)
+		ctx = l.addPeerFields(ctx, p)
 
-		l.Write(ctx, level, "finished client unary call", code, err, duration)
+		if logPayload && err == nil {
+			l.logPayload(ctx, "client response payload logged", FieldResponseContent, reply)
+		}
+
+		duration := time.Since(startTime)
+
+		l.writeCall(ctx, method, "finished client unary call", err, duration)
 
 		return err
 	}
 }
 
 // StreamClientInterceptor returns a new streaming client interceptor that optionally logs the execution of external gRPC calls.
+//
+// The call is logged once, when the stream ends, i.e. when RecvMsg returns an error, or io.EOF if the stream ends
+// successfully, or when the only response of a stream that is not server-streaming is received. The log has the total
+// duration of the stream and the number of messages sent and received. A stream that is never read to the end is not
+// logged.
 func StreamClientInterceptor(logger ctxd.Logger, opts ...Option) grpc.StreamClientInterceptor {
 	l := newClientLogger(logger, opts...)
 
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		startTime := time.Now()
 
+		ctx = l.propagateDebug(ctx)
 		ctx = clientLoggerContext(ctx, method, startTime)
-		clientStream, err := streamer(ctx, desc, cc, method, opts...)
+		ctx = l.outgoingMetadataContext(ctx)
+		ctx = l.targetContext(ctx, cc)
 
-		duration := time.Since(startTime)
+		l.logStartCall(ctx, "started client streaming call")
+
+		stopWatching := l.watchSlowCall(ctx, method, "client streaming call is still running", startTime)
 
-		code := l.errorToCode(err)
-		level := l.codeToLevel(code)
+		// The peer is set when the stream ends, reading it from the context of the stream would disable the retries.
+		var p *peer.Peer
 
-		l.Write(ctx, level, "finished client streaming call", code, err, duration)
+		if l.peerFields {
+			p = new(peer.Peer)
+			opts = append(opts, grpc.Peer(p))
+		}
+
+		clientStream, err := streamer(ctx, desc, cc, method, opts...)
 
-		return clientStream, err
+		if err != nil {
+			stopWatching()
+
+			l.writeCall(l.addPeerFields(ctx, p), method, "finished client streaming call", err, time.Since(startTime))
+
+			return nil, err
+		}
+
+		return &loggingClientStream{
+			ClientStream:  clientStream,
+			ctx:           ctx,
+			peer:          p,
+			logger:        l,
+			logPayload:    l.shouldLogPayloadOf(ctx, method),
+			method:        method,
+			startTime:     startTime,
+			stopWatching:  stopWatching,
+			serverStreams: desc == nil || desc.ServerStreams,
+		}, nil
 	}
 }
 
@@ -2,10 +2,12 @@ package ctxd
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -231,7 +233,7 @@ func TestStreamClientInterceptor(t *testing.T) {
 			context:     context.Background(),
 			loggerLevel: LogLevelDebug,
 			handler: func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
-				return nil, nil //nolint: nilnil
+				return &clientStream{}, nil
 			},
 			expectedLogMessage: `{
     "level": "debug",
@@ -242,6 +244,10 @@ func TestStreamClientInterceptor(t *testing.T) {
     "grpc.service": "grpctest.ItemService",
     "grpc.method": "ListItems",
     "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
     "grpc.code": "OK",
     "grpc.duration_ms": "<ignore-diff>"
 }`,
@@ -251,7 +257,7 @@ func TestStreamClientInterceptor(t *testing.T) {
 			context:     contextWithDeadline(time.Now().Add(time.Hour)),
 			loggerLevel: LogLevelDebug,
 			handler: func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
-				return nil, nil //nolint: nilnil
+				return &clientStream{}, nil
 			},
 			expectedLogMessage: `{
     "level": "debug",
@@ -263,6 +269,10 @@ func TestStreamClientInterceptor(t *testing.T) {
     "grpc.method": "ListItems",
     "grpc.request.deadline": "<ignore-diff>",
     "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
     "grpc.code": "OK",
     "grpc.duration_ms": "<ignore-diff>"
 }`,
@@ -275,10 +285,11 @@ func TestStreamClientInterceptor(t *testing.T) {
 
 			logger, buf := newCtxdLogger(tc.loggerLevel)
 
-			_, err := StreamClientInterceptor(logger, tc.options...)(tc.context, nil, nil, method, tc.handler)
+			stream, err := StreamClientInterceptor(logger, tc.options...)(tc.context, nil, nil, method, tc.handler)
 
 			if tc.expectedError == "" {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				assert.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
 			} else {
 				assert.EqualError(t, err, tc.expectedError)
 			}
@@ -287,3 +298,11 @@ func TestStreamClientInterceptor(t *testing.T) {
 		})
 	}
 }
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) RecvMsg(any) error {
+	return io.EOF
+}
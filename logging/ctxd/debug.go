@@ -0,0 +1,181 @@
+package ctxd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultDebugMetadataKey is the default metadata key for escalating the log level of a call to debug, e.g.
+// "x-debug-log: 1".
+const DefaultDebugMetadataKey = "x-debug-log"
+
+type debugValueCtxKey struct{}
+
+// DebugOption configures the escalation of the log level via metadata.
+type DebugOption func(c *debugConfig)
+
+// DebugVerifier checks whether the value of the debug metadata escalates the log level of a call of the full method
+// name, e.g. "/pkg.Service/Method".
+type DebugVerifier func(ctx context.Context, fullMethod string, value string) bool
+
+type debugConfig struct {
+	key    string
+	verify DebugVerifier
+}
+
+// WithDebugMetadata escalates the log level of a call to debug when its metadata has the debug key, see
+// DefaultDebugMetadataKey. The server interceptors mark the context of the calls with ctxd.WithDebug, so all the debug
+// entries of the call are logged, if the ctxd logger honors ctxd.IsDebug. The client interceptors propagate the debug
+// metadata to the downstream calls whose context is marked with ctxd.WithDebug.
+//
+// By default, the value of the metadata must be a true boolean, e.g. "1" or "true". Use WithDebugAllowlist,
+// WithDebugSigningKey or WithDebugVerifier to restrict who can escalate the log level.
+func WithDebugMetadata(opts ...DebugOption) Option {
+	return func(l *logger) {
+		c := &debugConfig{
+			key: DefaultDebugMetadataKey,
+			verify: func(_ context.Context, _ string, value string) bool {
+				debug, err := strconv.ParseBool(value)
+
+				return err == nil && debug
+			},
+		}
+
+		for _, o := range opts {
+			o(c)
+		}
+
+		l.debug = c
+	}
+}
+
+// WithDebugMetadataKey sets the metadata key for escalating the log level. The default is DefaultDebugMetadataKey.
+func WithDebugMetadataKey(key string) DebugOption {
+	return func(c *debugConfig) {
+		c.key = strings.ToLower(key)
+	}
+}
+
+// WithDebugAllowlist accepts only the given values of the debug metadata, e.g. the tokens given to the operators.
+func WithDebugAllowlist(values ...string) DebugOption {
+	allowed := make(map[string]struct{}, len(values))
+
+	for _, v := range values {
+		allowed[v] = struct{}{}
+	}
+
+	return WithDebugVerifier(func(_ context.Context, _ string, value string) bool {
+		_, ok := allowed[value]
+
+		return ok
+	})
+}
+
+// WithDebugSigningKey accepts only the values of the debug metadata that are signed with the key and not expired, and,
+// if the token is scoped, only for the methods of the token, see SignDebugToken.
+func WithDebugSigningKey(key []byte) DebugOption {
+	return WithDebugVerifier(func(_ context.Context, fullMethod string, value string) bool {
+		encoded, signature, ok := strings.Cut(value, ".")
+		if !ok || !hmac.Equal([]byte(signature), []byte(debugSignature(key, encoded))) {
+			return false
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			return false
+		}
+
+		var claims debugClaims
+
+		if err := json.Unmarshal(payload, &claims); err != nil {
+			return false
+		}
+
+		if !time.Now().Before(time.Unix(claims.ExpiresAt, 0)) {
+			return false
+		}
+
+		return len(claims.Methods) == 0 || slices.Contains(claims.Methods, fullMethod)
+	})
+}
+
+// WithDebugVerifier sets the function that checks the value of the debug metadata.
+func WithDebugVerifier(verify DebugVerifier) DebugOption {
+	return func(c *debugConfig) {
+		c.verify = verify
+	}
+}
+
+// SignDebugToken signs a token, e.g. a ticket number, for escalating the log level of a call with the debug metadata.
+// The signed token expires at the given time and, if methods are given, only escalates the calls of these full method
+// names, e.g. "/pkg.Service/Method". See WithDebugSigningKey.
+func SignDebugToken(key []byte, token string, expiresAt time.Time, methods ...string) string {
+	payload, _ := json.Marshal(debugClaims{ //nolint: errcheck,errchkjson
+		Token:     token,
+		ExpiresAt: expiresAt.Unix(),
+		Methods:   methods,
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + debugSignature(key, encoded)
+}
+
+// debugClaims is the payload of a signed debug token.
+type debugClaims struct {
+	Token     string   `json:"tok"`
+	ExpiresAt int64    `json:"exp"`
+	Methods   []string `json:"mth,omitempty"`
+}
+
+func debugSignature(key []byte, encoded string) string {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// debugContext marks the context with ctxd.WithDebug if the incoming metadata escalates the log level.
+func (l *logger) debugContext(ctx context.Context, fullMethod string) context.Context {
+	if l.debug == nil {
+		return ctx
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, value := range md.Get(l.debug.key) {
+		if l.debug.verify(ctx, fullMethod, value) {
+			return context.WithValue(ctxd.WithDebug(ctx), debugValueCtxKey{}, value)
+		}
+	}
+
+	return ctx
+}
+
+// propagateDebug adds the debug metadata to the outgoing metadata if the context is marked with ctxd.WithDebug.
+func (l *logger) propagateDebug(ctx context.Context) context.Context {
+	if l.debug == nil || !ctxd.IsDebug(ctx) {
+		return ctx
+	}
+
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(l.debug.key)) > 0 {
+		return ctx
+	}
+
+	value, ok := ctx.Value(debugValueCtxKey{}).(string)
+	if !ok {
+		value = "1"
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, l.debug.key, value)
+}
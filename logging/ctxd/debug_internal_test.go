@@ -0,0 +1,208 @@
+package ctxd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor_DebugMetadata(t *testing.T) {
+	t.Parallel()
+
+	signingKey := []byte("secret")
+	expiresAt := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		scenario      string
+		metadata      metadata.MD
+		options       []DebugOption
+		expectedDebug bool
+	}{
+		{
+			scenario: "no metadata",
+		},
+		{
+			scenario:      "enabled",
+			metadata:      metadata.Pairs(DefaultDebugMetadataKey, "1"),
+			expectedDebug: true,
+		},
+		{
+			scenario: "disabled",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, "0"),
+		},
+		{
+			scenario: "malformed",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, "yes please"),
+		},
+		{
+			scenario:      "custom key",
+			metadata:      metadata.Pairs("x-verbose", "true"),
+			options:       []DebugOption{WithDebugMetadataKey("X-Verbose")},
+			expectedDebug: true,
+		},
+		{
+			scenario:      "allowed",
+			metadata:      metadata.Pairs(DefaultDebugMetadataKey, "ticket-42"),
+			options:       []DebugOption{WithDebugAllowlist("ticket-42")},
+			expectedDebug: true,
+		},
+		{
+			scenario: "not allowed",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, "1"),
+			options:  []DebugOption{WithDebugAllowlist("ticket-42")},
+		},
+		{
+			scenario:      "signed",
+			metadata:      metadata.Pairs(DefaultDebugMetadataKey, SignDebugToken(signingKey, "ticket-42", expiresAt)),
+			options:       []DebugOption{WithDebugSigningKey(signingKey)},
+			expectedDebug: true,
+		},
+		{
+			scenario: "signed with another key",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, SignDebugToken([]byte("other"), "ticket-42", expiresAt)),
+			options:  []DebugOption{WithDebugSigningKey(signingKey)},
+		},
+		{
+			scenario: "signed but expired",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, SignDebugToken(signingKey, "ticket-42", time.Now().Add(-time.Second))),
+			options:  []DebugOption{WithDebugSigningKey(signingKey)},
+		},
+		{
+			scenario:      "signed for the method",
+			metadata:      metadata.Pairs(DefaultDebugMetadataKey, SignDebugToken(signingKey, "ticket-42", expiresAt, "/grpctest.ItemService/GetItem")),
+			options:       []DebugOption{WithDebugSigningKey(signingKey)},
+			expectedDebug: true,
+		},
+		{
+			scenario: "signed for another method",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, SignDebugToken(signingKey, "ticket-42", expiresAt, "/grpctest.ItemService/DeleteItem")),
+			options:  []DebugOption{WithDebugSigningKey(signingKey)},
+		},
+		{
+			scenario: "tampered payload",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, func() string {
+				// The expiry of an expired token is replaced.
+				payload, _, _ := strings.Cut(SignDebugToken(signingKey, "ticket-42", expiresAt), ".")
+				_, signature, _ := strings.Cut(SignDebugToken(signingKey, "ticket-42", time.Now().Add(-time.Second)), ".")
+
+				return payload + "." + signature
+			}()),
+			options: []DebugOption{WithDebugSigningKey(signingKey)},
+		},
+		{
+			scenario: "not signed",
+			metadata: metadata.Pairs(DefaultDebugMetadataKey, "1"),
+			options:  []DebugOption{WithDebugSigningKey(signingKey)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+			info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+			ctx := metadata.NewIncomingContext(context.Background(), tc.metadata)
+
+			_, err := UnaryServerInterceptor(logger, WithDebugMetadata(tc.options...))(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+				assert.Equal(t, tc.expectedDebug, ctxd.IsDebug(ctx))
+
+				logger.Debug(ctx, "handling call")
+
+				return 42, nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedDebug, len(filterLogMessages(buf.String(), "handling call")) > 0)
+		})
+	}
+}
+
+func TestStreamServerInterceptor_DebugMetadata(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(DefaultDebugMetadataKey, "true"))
+
+	err := StreamServerInterceptor(logger, WithDebugMetadata())(nil, serverStreamWithContext(ctx), info, func(_ any, stream grpc.ServerStream) error {
+		logger.Debug(stream.Context(), "handling call")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, filterLogMessages(buf.String(), "handling call"))
+}
+
+func TestUnaryClientInterceptor_DebugMetadata(t *testing.T) {
+	t.Parallel()
+
+	signed := SignDebugToken([]byte("secret"), "ticket-42", time.Now().Add(time.Hour))
+
+	testCases := []struct {
+		scenario      string
+		context       func() context.Context
+		expectedValue []string
+	}{
+		{
+			scenario: "not debug",
+			context:  context.Background,
+		},
+		{
+			scenario: "debug",
+			context: func() context.Context {
+				return ctxd.WithDebug(context.Background())
+			},
+			expectedValue: []string{"1"},
+		},
+		{
+			scenario: "escalated by the server",
+			context: func() context.Context {
+				l := defaultLogger(nil)
+				WithDebugMetadata(WithDebugSigningKey([]byte("secret")))(l)
+
+				return l.debugContext(metadata.NewIncomingContext(context.Background(), metadata.Pairs(DefaultDebugMetadataKey, signed)), "/grpctest.ItemService/ListItems")
+			},
+			expectedValue: []string{signed},
+		},
+		{
+			scenario: "already in the metadata",
+			context: func() context.Context {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultDebugMetadataKey, "true")
+
+				return ctxd.WithDebug(ctx)
+			},
+			expectedValue: []string{"true"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+
+			err := UnaryClientInterceptor(logger, WithDebugMetadata())(tc.context(), "/grpctest.ItemService/GetItem", nil, nil, nil,
+				func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+					md, _ := metadata.FromOutgoingContext(ctx)
+
+					assert.Equal(t, tc.expectedValue, md.Get(DefaultDebugMetadataKey))
+
+					return nil
+				},
+			)
+			require.NoError(t, err)
+
+			// The client calls are logged at debug level by default.
+			assert.Equal(t, tc.expectedValue != nil, buf.Len() > 0)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package ctxd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// FieldErrorReason is a context field for the reason of the errdetails.ErrorInfo of an error.
+	FieldErrorReason = "grpc.error.reason"
+	// FieldErrorDomain is a context field for the domain of the errdetails.ErrorInfo of an error.
+	FieldErrorDomain = "grpc.error.domain"
+	// FieldErrorMetadata is a context field for the metadata of the errdetails.ErrorInfo of an error.
+	FieldErrorMetadata = "grpc.error.metadata"
+	// FieldErrorFieldViolations is a context field for the field violations of the errdetails.BadRequest of an error.
+	FieldErrorFieldViolations = "grpc.error.field_violations"
+	// FieldErrorRetryDelay is a context field for the retry delay, in ms, of the errdetails.RetryInfo of an error.
+	FieldErrorRetryDelay = "grpc.error.retry_delay_ms"
+	// FieldErrorQuotaViolations is a context field for the violations of the errdetails.QuotaFailure of an error.
+	FieldErrorQuotaViolations = "grpc.error.quota_violations"
+)
+
+// errorContext adds the details of the error to the context fields: the tuples of a ctxd.StructuredError, and the
+// errdetails.ErrorInfo, errdetails.BadRequest, errdetails.RetryInfo and errdetails.QuotaFailure of a status error.
+func errorContext(ctx context.Context, err error) context.Context {
+	var se ctxd.StructuredError
+
+	if errors.As(err, &se) {
+		if tuples := se.Tuples(); len(tuples) > 0 {
+			ctx = ctxd.AddFields(ctx, tuples...)
+		}
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return ctx
+	}
+
+	var fields []any
+
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			fields = append(fields,
+				FieldErrorReason, d.GetReason(),
+				FieldErrorDomain, d.GetDomain(),
+			)
+
+			if len(d.GetMetadata()) > 0 {
+				fields = append(fields, FieldErrorMetadata, d.GetMetadata())
+			}
+
+		case *errdetails.BadRequest:
+			violations := make([]map[string]string, 0, len(d.GetFieldViolations()))
+
+			for _, v := range d.GetFieldViolations() {
+				violations = append(violations, map[string]string{
+					"field":       v.GetField(),
+					"description": v.GetDescription(),
+				})
+			}
+
+			fields = append(fields, FieldErrorFieldViolations, violations)
+
+		case *errdetails.RetryInfo:
+			fields = append(fields, FieldErrorRetryDelay, DurationInMilliseconds(d.GetRetryDelay().AsDuration()))
+
+		case *errdetails.QuotaFailure:
+			violations := make([]map[string]string, 0, len(d.GetViolations()))
+
+			for _, v := range d.GetViolations() {
+				violations = append(violations, map[string]string{
+					"subject":     v.GetSubject(),
+					"description": v.GetDescription(),
+				})
+			}
+
+			fields = append(fields, FieldErrorQuotaViolations, violations)
+		}
+	}
+
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return ctxd.AddFields(ctx, fields...)
+}
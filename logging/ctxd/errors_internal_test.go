@@ -0,0 +1,192 @@
+package ctxd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestUnaryServerInterceptor_ErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	newStatusError := func(t *testing.T, code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+		t.Helper()
+
+		st, err := status.New(code, msg).WithDetails(details...)
+		require.NoError(t, err)
+
+		return st.Err()
+	}
+
+	testCases := []struct {
+		scenario           string
+		error              func(t *testing.T) error
+		expectedLogMessage string
+	}{
+		{
+			scenario: "error info",
+			error: func(t *testing.T) error {
+				t.Helper()
+
+				return newStatusError(t, codes.PermissionDenied, "permission denied", &errdetails.ErrorInfo{
+					Reason:   "API_DISABLED",
+					Domain:   "example.com",
+					Metadata: map[string]string{"service": "items"},
+				})
+			},
+			expectedLogMessage: `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "PermissionDenied",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = PermissionDenied desc = permission denied",
+    "grpc.error.reason": "API_DISABLED",
+    "grpc.error.domain": "example.com",
+    "grpc.error.metadata": {"service": "items"}
+}`,
+		},
+		{
+			scenario: "bad request",
+			error: func(t *testing.T) error {
+				t.Helper()
+
+				return newStatusError(t, codes.InvalidArgument, "invalid argument", &errdetails.BadRequest{
+					FieldViolations: []*errdetails.BadRequest_FieldViolation{
+						{Field: "name", Description: "must not be empty"},
+						{Field: "price", Description: "must be positive"},
+					},
+				})
+			},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "InvalidArgument",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = InvalidArgument desc = invalid argument",
+    "grpc.error.field_violations": [
+        {"field": "name", "description": "must not be empty"},
+        {"field": "price", "description": "must be positive"}
+    ]
+}`,
+		},
+		{
+			scenario: "retry info and quota failure",
+			error: func(t *testing.T) error {
+				t.Helper()
+
+				return newStatusError(t, codes.ResourceExhausted, "resource exhausted",
+					&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+					&errdetails.QuotaFailure{
+						Violations: []*errdetails.QuotaFailure_Violation{
+							{Subject: "project:42", Description: "daily limit exceeded"},
+						},
+					},
+				)
+			},
+			expectedLogMessage: `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "ResourceExhausted",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = ResourceExhausted desc = resource exhausted",
+    "grpc.error.retry_delay_ms": 1500,
+    "grpc.error.quota_violations": [
+        {"subject": "project:42", "description": "daily limit exceeded"}
+    ]
+}`,
+		},
+		{
+			scenario: "structured error",
+			error: func(*testing.T) error {
+				return fmt.Errorf("get item: %w", ctxd.NewError(context.Background(), "item not found", "item.id", 42))
+			},
+			expectedLogMessage: `{
+    "level": "error",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "Unknown",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "<ignore-diff>",
+    "item.id": 42
+}`,
+		},
+		{
+			scenario: "plain error",
+			error: func(*testing.T) error {
+				return errors.New("failure")
+			},
+			expectedLogMessage: `{
+    "level": "error",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "Unknown",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "failure"
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+			info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+			handlerErr := tc.error(t)
+
+			_, err := UnaryServerInterceptor(logger)(context.Background(), nil, info, func(context.Context, any) (any, error) {
+				return nil, handlerErr
+			})
+			require.Equal(t, handlerErr, err)
+
+			assertLogMessage(t, tc.expectedLogMessage, buf.String())
+		})
+	}
+}
+
+func TestErrorContext_NoDetails(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	require.Equal(t, ctx, errorContext(ctx, status.Error(codes.Internal, "internal error")))
+}
@@ -0,0 +1,108 @@
+package ctxd
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// LevelFunc decides the log level of a finished call.
+type LevelFunc func(ctx context.Context, fullMethod string, code codes.Code) LogLevel
+
+// LevelPolicyOption configures a LevelPolicy.
+type LevelPolicyOption func(p *LevelPolicy)
+
+// LevelPolicy is a table of log levels keyed by method and code, e.g. NotFound on "/pkg.Users/Get" is logged at debug
+// level, but at warn level on "/pkg.Orders/Get".
+//
+// The level of a call is resolved in this order:
+//   - the level of the full method name and the code, e.g. "/pkg.Service/Method".
+//   - the level of the first pattern that matches the full method name, with the code, in the order they are added.
+//   - the level of the service and the code, e.g. "pkg.Service".
+//   - the level of the CodeToLevel of the interceptor, see WithLevels.
+type LevelPolicy struct {
+	methods  map[string]map[codes.Code]LogLevel
+	services map[string]map[codes.Code]LogLevel
+	patterns []patternLevel
+}
+
+type patternLevel struct {
+	pattern string
+	code    codes.Code
+	level   LogLevel
+}
+
+// NewLevelPolicy creates a new level policy.
+func NewLevelPolicy(opts ...LevelPolicyOption) *LevelPolicy {
+	p := &LevelPolicy{
+		methods:  make(map[string]map[codes.Code]LogLevel),
+		services: make(map[string]map[codes.Code]LogLevel),
+	}
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	return p
+}
+
+// WithMethodLevel sets the log level of a code for a full method name, e.g. "/pkg.Service/Method".
+func WithMethodLevel(fullMethod string, code codes.Code, level LogLevel) LevelPolicyOption {
+	return func(p *LevelPolicy) {
+		setLevel(p.methods, fullMethod, code, level)
+	}
+}
+
+// WithServiceLevel sets the log level of a code for all the methods of a service, e.g. "pkg.Service".
+func WithServiceLevel(service string, code codes.Code, level LogLevel) LevelPolicyOption {
+	return func(p *LevelPolicy) {
+		setLevel(p.services, service, code, level)
+	}
+}
+
+// WithPatternLevel sets the log level of a code for all the methods whose full name matches the glob pattern, e.g.
+// "/pkg.Service/*". See path.Match for the pattern syntax. A malformed pattern never matches.
+func WithPatternLevel(pattern string, code codes.Code, level LogLevel) LevelPolicyOption {
+	return func(p *LevelPolicy) {
+		p.patterns = append(p.patterns, patternLevel{pattern: pattern, code: code, level: level})
+	}
+}
+
+// Level returns the log level of the code for the full method name, if there is one in the policy.
+func (p *LevelPolicy) Level(fullMethod string, code codes.Code) (LogLevel, bool) {
+	if level, ok := p.methods[fullMethod][code]; ok {
+		return level, true
+	}
+
+	for _, pl := range p.patterns {
+		if pl.code != code {
+			continue
+		}
+
+		if ok, err := path.Match(pl.pattern, fullMethod); err == nil && ok {
+			return pl.level, true
+		}
+	}
+
+	if level, ok := p.services[serviceName(fullMethod)][code]; ok {
+		return level, true
+	}
+
+	return 0, false
+}
+
+func setLevel(levels map[string]map[codes.Code]LogLevel, key string, code codes.Code, level LogLevel) {
+	if levels[key] == nil {
+		levels[key] = make(map[codes.Code]LogLevel)
+	}
+
+	levels[key][code] = level
+}
+
+func serviceName(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return service
+}
@@ -0,0 +1,156 @@
+package ctxd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor_LevelPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := NewLevelPolicy(
+		WithMethodLevel("/pkg.Users/Get", codes.NotFound, LogLevelDebug),
+		WithMethodLevel("/pkg.Orders/Get", codes.NotFound, LogLevelWarn),
+	)
+
+	testCases := []struct {
+		scenario      string
+		fullMethod    string
+		options       []Option
+		expectedLevel string
+	}{
+		{
+			scenario:      "policy",
+			fullMethod:    "/pkg.Orders/Get",
+			options:       []Option{WithLevelPolicy(policy)},
+			expectedLevel: "warn",
+		},
+		{
+			scenario:      "policy of another method",
+			fullMethod:    "/pkg.Users/Get",
+			options:       []Option{WithLevelPolicy(policy)},
+			expectedLevel: "debug",
+		},
+		{
+			scenario:   "fallback to the code to level",
+			fullMethod: "/pkg.Items/Get",
+			options: []Option{
+				WithLevelPolicy(policy),
+				WithLevels(func(codes.Code) LogLevel {
+					return LogLevelError
+				}),
+			},
+			expectedLevel: "error",
+		},
+		{
+			scenario:   "level func",
+			fullMethod: "/pkg.Orders/Get",
+			options: []Option{
+				WithLevelPolicy(policy),
+				WithLevelFunc(func(_ context.Context, fullMethod string, code codes.Code) LogLevel {
+					if fullMethod == "/pkg.Orders/Get" && code == codes.NotFound {
+						return LogLevelInfo
+					}
+
+					return LogLevelError
+				}),
+			},
+			expectedLevel: "info",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelDebug)
+			info := &grpc.UnaryServerInfo{FullMethod: tc.fullMethod}
+
+			_, _ = UnaryServerInterceptor(logger, tc.options...)(context.Background(), nil, info, func(context.Context, any) (any, error) { //nolint: errcheck
+				return nil, status.Error(codes.NotFound, "not found")
+			})
+
+			assert.Contains(t, buf.String(), `"level":"`+tc.expectedLevel+`"`)
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_LevelFunc(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	interceptor := UnaryClientInterceptor(logger, WithLevelFunc(func(ctx context.Context, fullMethod string, code codes.Code) LogLevel {
+		assert.NotNil(t, ctx)
+		assert.Equal(t, "/pkg.Orders/Get", fullMethod)
+		assert.Equal(t, codes.NotFound, code)
+
+		return LogLevelWarn
+	}))
+
+	_ = interceptor(context.Background(), "/pkg.Orders/Get", nil, nil, nil, //nolint: errcheck
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "not found")
+		},
+	)
+
+	assert.Contains(t, buf.String(), `"level":"warn"`)
+}
+
+func TestLogger_LevelOf_FuncOverridesPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := NewLevelPolicy(WithMethodLevel("/pkg.Orders/Get", codes.NotFound, LogLevelDebug))
+	levelFunc := func(context.Context, string, codes.Code) LogLevel {
+		return LogLevelError
+	}
+
+	testCases := []struct {
+		scenario string
+		options  []Option
+	}{
+		{
+			scenario: "policy first",
+			options:  []Option{WithLevelPolicy(policy), WithLevelFunc(levelFunc)},
+		},
+		{
+			scenario: "func first",
+			options:  []Option{WithLevelFunc(levelFunc), WithLevelPolicy(policy)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			l := newClientLogger(nil, tc.options...)
+
+			_, level := l.levelOf(context.Background(), "/pkg.Orders/Get", codes.NotFound, 0)
+
+			assert.Equal(t, LogLevelError, level)
+		})
+	}
+}
+
+func TestLogger_LevelOf_PolicyFallback(t *testing.T) {
+	t.Parallel()
+
+	policy := NewLevelPolicy(WithMethodLevel("/pkg.Orders/Get", codes.NotFound, LogLevelWarn))
+
+	l := newServerLogger(nil, WithLevelPolicy(policy), WithLevels(func(codes.Code) LogLevel {
+		return LogLevelImportant
+	}))
+
+	_, level := l.levelOf(context.Background(), "/pkg.Orders/Get", codes.NotFound, 0)
+
+	assert.Equal(t, LogLevelWarn, level)
+
+	_, level = l.levelOf(context.Background(), "/pkg.Orders/List", codes.NotFound, 0)
+
+	assert.Equal(t, LogLevelImportant, level, "the other calls are logged at the level of WithLevels")
+}
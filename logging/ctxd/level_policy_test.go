@@ -0,0 +1,89 @@
+package ctxd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"github.com/nhatthm/go-grpc-middleware/logging/ctxd"
+)
+
+func TestLevelPolicy_Level(t *testing.T) {
+	t.Parallel()
+
+	p := ctxd.NewLevelPolicy(
+		ctxd.WithMethodLevel("/pkg.Users/Get", codes.NotFound, ctxd.LogLevelDebug),
+		ctxd.WithMethodLevel("/pkg.Orders/Get", codes.NotFound, ctxd.LogLevelWarn),
+		ctxd.WithPatternLevel("/pkg.Orders/*", codes.NotFound, ctxd.LogLevelError),
+		ctxd.WithPatternLevel("/pkg.*/List", codes.InvalidArgument, ctxd.LogLevelWarn),
+		ctxd.WithPatternLevel("[", codes.InvalidArgument, ctxd.LogLevelError),
+		ctxd.WithServiceLevel("pkg.Orders", codes.NotFound, ctxd.LogLevelDebug),
+		ctxd.WithServiceLevel("pkg.Orders", codes.InvalidArgument, ctxd.LogLevelInfo),
+	)
+
+	testCases := []struct {
+		scenario      string
+		fullMethod    string
+		code          codes.Code
+		expectedLevel ctxd.LogLevel
+		expectedFound bool
+	}{
+		{
+			scenario:      "method",
+			fullMethod:    "/pkg.Users/Get",
+			code:          codes.NotFound,
+			expectedLevel: ctxd.LogLevelDebug,
+			expectedFound: true,
+		},
+		{
+			scenario:      "method over pattern",
+			fullMethod:    "/pkg.Orders/Get",
+			code:          codes.NotFound,
+			expectedLevel: ctxd.LogLevelWarn,
+			expectedFound: true,
+		},
+		{
+			scenario:      "pattern over service",
+			fullMethod:    "/pkg.Orders/Delete",
+			code:          codes.NotFound,
+			expectedLevel: ctxd.LogLevelError,
+			expectedFound: true,
+		},
+		{
+			scenario:      "pattern of another code",
+			fullMethod:    "/pkg.Orders/Delete",
+			code:          codes.InvalidArgument,
+			expectedLevel: ctxd.LogLevelInfo,
+			expectedFound: true,
+		},
+		{
+			scenario:      "pattern across services",
+			fullMethod:    "/pkg.Users/List",
+			code:          codes.InvalidArgument,
+			expectedLevel: ctxd.LogLevelWarn,
+			expectedFound: true,
+		},
+		{
+			scenario:   "code not in the method",
+			fullMethod: "/pkg.Users/Get",
+			code:       codes.Internal,
+		},
+		{
+			scenario:   "unknown method",
+			fullMethod: "/pkg.Items/Get",
+			code:       codes.NotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			level, found := p.Level(tc.fullMethod, tc.code)
+
+			assert.Equal(t, tc.expectedLevel, level)
+			assert.Equal(t, tc.expectedFound, found)
+		})
+	}
+}
@@ -26,11 +26,26 @@ const (
 	FieldDuration = "grpc.duration_ms"
 	// FieldCode is a context field for return code.
 	FieldCode = "grpc.code"
+	// FieldRequestContent is a context field for the request payload.
+	FieldRequestContent = "grpc.request.content"
+	// FieldResponseContent is a context field for the response payload.
+	FieldResponseContent = "grpc.response.content"
+	// FieldMessagesSent is a context field for the number of messages sent in a stream.
+	FieldMessagesSent = "grpc.msg.sent"
+	// FieldMessagesReceived is a context field for the number of messages received in a stream.
+	FieldMessagesReceived = "grpc.msg.received"
+	// FieldBytesSent is a context field for the serialized size of the messages sent in a stream.
+	FieldBytesSent = "grpc.bytes.sent"
+	// FieldBytesReceived is a context field for the serialized size of the messages received in a stream.
+	FieldBytesReceived = "grpc.bytes.received"
 )
 
 // CodeToLevel function defines the mapping between gRPC return codes and interceptor log level.
 type CodeToLevel func(code codes.Code) LogLevel
 
+// PayloadDecider decides whether the request and response payloads of a call should be logged.
+type PayloadDecider func(ctx context.Context, fullMethod string) bool
+
 // MessageProducer produces a user defined log message.
 type MessageProducer func(ctx context.Context, msg string, code codes.Code, err error, duration time.Duration) (context.Context, string)
 
@@ -38,12 +53,36 @@ type MessageProducer func(ctx context.Context, msg string, code codes.Code, err
 type Option func(l *logger)
 
 type logger struct {
-	log ctxd.Logger
+	log  ctxd.Logger
+	kind string
 
 	shouldLog      grpcLogging.Decider
 	errorToCode    grpcLogging.ErrorToCode
 	codeToLevel    CodeToLevel
+	levelPolicy    *LevelPolicy
+	levelFunc      LevelFunc
 	produceMessage MessageProducer
+
+	shouldLogPayload PayloadDecider
+	maxPayloadSize   int
+	redactor         *Redactor
+
+	metadataFields []metadataField
+	peerFields     bool
+
+	logStart   bool
+	startLevel LogLevel
+
+	slowThreshold        time.Duration
+	methodSlowThresholds map[string]time.Duration
+	slowProgress         bool
+	slowProgressInterval time.Duration
+
+	sampling *sampler
+	debug    *debugConfig
+
+	stackTraceCodes map[codes.Code]struct{}
+	errorFormatter  ErrorFormatter
 }
 
 func defaultLogger(log ctxd.Logger) *logger {
@@ -59,8 +98,50 @@ func defaultLogger(log ctxd.Logger) *logger {
 }
 
 func (l *logger) Write(ctx context.Context, level LogLevel, msg string, code codes.Code, err error, duration time.Duration) {
+	if l.errorFormatter != nil {
+		ctx = context.WithValue(ctx, errorFormatterCtxKey{}, l.errorFormatter)
+	}
+
 	ctx, msg = l.produceMessage(ctx, msg, code, err, duration)
 
+	l.write(ctx, level, msg)
+}
+
+// writeCall logs a finished call.
+func (l *logger) writeCall(ctx context.Context, fullMethod string, msg string, err error, duration time.Duration) {
+	code := l.errorToCode(err)
+	ctx, level := l.levelOf(ctx, fullMethod, code, duration)
+
+	if !l.sample(fullMethod, level, code) {
+		return
+	}
+
+	l.Write(ctx, level, msg, code, err, duration)
+}
+
+// levelOf returns the log level of a finished call.
+func (l *logger) levelOf(ctx context.Context, fullMethod string, code codes.Code, duration time.Duration) (context.Context, LogLevel) {
+	level := l.codeToLevel(code)
+
+	switch {
+	case l.levelFunc != nil:
+		level = l.levelFunc(ctx, fullMethod, code)
+
+	case l.levelPolicy != nil:
+		if policyLevel, ok := l.levelPolicy.Level(fullMethod, code); ok {
+			level = policyLevel
+		}
+	}
+
+	if threshold := l.slowCallThreshold(fullMethod); threshold > 0 && duration >= threshold {
+		ctx = ctxd.AddFields(ctx, FieldSlow, true)
+		level = max(level, LogLevelWarn)
+	}
+
+	return ctx, level
+}
+
+func (l *logger) write(ctx context.Context, level LogLevel, msg string) {
 	switch level {
 	case LogLevelDebug:
 		l.log.Debug(ctx, msg)
@@ -89,6 +170,22 @@ func WithLevels(f CodeToLevel) Option {
 	}
 }
 
+// WithLevelFunc customizes the function for deciding the log level of a call by its method and code. It overrides
+// WithLevels and WithLevelPolicy, whatever the order of the options.
+func WithLevelFunc(f LevelFunc) Option {
+	return func(l *logger) {
+		l.levelFunc = f
+	}
+}
+
+// WithLevelPolicy sets the log levels of the methods and codes that are in the policy. The other calls are logged at
+// the level of WithLevels.
+func WithLevelPolicy(p *LevelPolicy) Option {
+	return func(l *logger) {
+		l.levelPolicy = p
+	}
+}
+
 // WithCodes customizes the function for mapping errors to error codes.
 func WithCodes(f grpcLogging.ErrorToCode) Option {
 	return func(l *logger) {
@@ -103,6 +200,47 @@ func WithMessageProducer(f MessageProducer) Option {
 	}
 }
 
+// WithPayloadLogging enables the logging of the request and response payloads, at debug level, for the calls accepted
+// by the decider. For streams, every message is logged. The payload entries have the context fields of the call and go
+// through the MessageProducer, see IsPayloadEntry.
+func WithPayloadLogging(d PayloadDecider) Option {
+	return func(l *logger) {
+		l.shouldLogPayload = d
+	}
+}
+
+// WithMaxPayloadSize sets the maximum size, in bytes, of a logged payload. Longer payloads are truncated. A zero size
+// means no limit.
+func WithMaxPayloadSize(size int) Option {
+	return func(l *logger) {
+		l.maxPayloadSize = size
+	}
+}
+
+// WithStartCallLogging logs the start of the calls at the given level, with the same context fields as the end of the
+// calls, so the calls that hang can be found before they end. On the server side, the calls that are not accepted by
+// the decider, with a nil error, are not logged.
+func WithStartCallLogging(level LogLevel) Option {
+	return func(l *logger) {
+		l.logStart = true
+		l.startLevel = level
+	}
+}
+
+// logStartCall logs the start of a call, if enabled.
+func (l *logger) logStartCall(ctx context.Context, msg string) {
+	if l.logStart {
+		l.write(ctx, l.startLevel, msg)
+	}
+}
+
+// WithRedactor masks the sensitive values of the payloads and the metadata before they are logged.
+func WithRedactor(r *Redactor) Option {
+	return func(l *logger) {
+		l.redactor = r
+	}
+}
+
 // DefaultCodeToLevel is the default implementation of gRPC return codes and interceptor log level for server side.
 func DefaultCodeToLevel(code codes.Code) LogLevel { //nolint: cyclop,dupl
 	switch code {
@@ -192,15 +330,37 @@ func DurationInMilliseconds(d time.Duration) float32 {
 	return float32(d.Nanoseconds()/1000) / 1000
 }
 
-// DefaultMessageProducer sets the log message and fields.
+// DefaultMessageProducer sets the log message and fields. The statistics of the streams are added too, see
+// StreamStatsFromContext, and the details of the error, i.e. the tuples of a ctxd.StructuredError and the errdetails
+// of a status error. The error is rendered with the formatter of WithErrorFormatter, if any. The payload entries are
+// left as they are, they have no code, duration or error.
 func DefaultMessageProducer(ctx context.Context, msg string, code codes.Code, err error, duration time.Duration) (context.Context, string) {
+	if IsPayloadEntry(ctx) {
+		return ctx, msg
+	}
+
 	ctx = ctxd.AddFields(ctx,
 		FieldCode, code,
 		FieldDuration, DurationInMilliseconds(duration),
 	)
 
+	if stats, ok := StreamStatsFromContext(ctx); ok {
+		ctx = ctxd.AddFields(ctx,
+			FieldMessagesSent, stats.MessagesSent,
+			FieldMessagesReceived, stats.MessagesReceived,
+			FieldBytesSent, stats.BytesSent,
+			FieldBytesReceived, stats.BytesReceived,
+		)
+	}
+
 	if err != nil {
-		ctx = ctxd.AddFields(ctx, "error", err)
+		if format, ok := ErrorFormatterFromContext(ctx); ok {
+			ctx = ctxd.AddFields(ctx, format(err)...)
+		} else {
+			ctx = ctxd.AddFields(ctx, "error", err)
+		}
+
+		ctx = errorContext(ctx, err)
 	}
 
 	return ctx, msg
@@ -3,6 +3,7 @@ package ctxd
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -117,6 +118,23 @@ func assertLogMessage(t assert.TestingT, expected, actual string) bool { //nolin
 	return assertjson.Equal(t, []byte(expected), []byte(actual))
 }
 
+func assertLogMessages(t *testing.T, expected []string, actual string) {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(actual), "\n")
+	if actual == "" {
+		lines = nil
+	}
+
+	if !assert.Len(t, lines, len(expected), "unexpected number of messages: %s", actual) {
+		return
+	}
+
+	for i, line := range lines {
+		assertjson.Equal(t, []byte(expected[i]), []byte(line))
+	}
+}
+
 func contextWithDeadline(deadline time.Time) context.Context {
 	//goland:noinspection GoVetLostCancel
 	//nolint: govet
@@ -0,0 +1,102 @@
+package ctxd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/metadata"
+)
+
+// FieldMetadataPrefix is the prefix of the context fields of the metadata keys that are not renamed, e.g.
+// "grpc.metadata.x-request-id".
+const FieldMetadataPrefix = "grpc.metadata."
+
+type metadataField struct {
+	key   string
+	field string
+}
+
+// WithMetadataFields copies the values of the metadata keys into the context fields, so every log line of the call
+// carries them. The server interceptors read the incoming metadata, the client interceptors read the outgoing metadata.
+// The fields are named with FieldMetadataPrefix, e.g. "grpc.metadata.x-request-id".
+func WithMetadataFields(keys ...string) Option {
+	return func(l *logger) {
+		for _, k := range keys {
+			l.addMetadataField(k, FieldMetadataPrefix+strings.ToLower(k))
+		}
+	}
+}
+
+// WithMetadataField copies the values of the metadata key into the context field with the given name, e.g.
+// "x-tenant-id" into "tenant". See WithMetadataFields.
+func WithMetadataField(key, field string) Option {
+	return func(l *logger) {
+		l.addMetadataField(key, field)
+	}
+}
+
+func (l *logger) addMetadataField(key, field string) {
+	key = strings.ToLower(key)
+
+	for i, f := range l.metadataFields {
+		if f.key == key {
+			l.metadataFields[i].field = field
+
+			return
+		}
+	}
+
+	l.metadataFields = append(l.metadataFields, metadataField{key: key, field: field})
+}
+
+// metadataContext adds the allowed metadata keys to the context fields. A key with one value is logged as a string,
+// a key with many values is logged as a list. The missing keys are omitted.
+func (l *logger) metadataContext(ctx context.Context, md metadata.MD) context.Context {
+	if len(l.metadataFields) == 0 || len(md) == 0 {
+		return ctx
+	}
+
+	fields := make([]any, 0, 2*len(l.metadataFields))
+
+	for _, f := range l.metadataFields {
+		values := md.Get(f.key)
+		if len(values) == 0 {
+			continue
+		}
+
+		if l.redactor != nil && l.redactor.isSensitiveMetadataKey(f.key) {
+			masked := make([]string, len(values))
+
+			for i, v := range values {
+				masked[i] = l.redactor.maskString(v)
+			}
+
+			values = masked
+		}
+
+		if len(values) == 1 {
+			fields = append(fields, f.field, values[0])
+		} else {
+			fields = append(fields, f.field, values)
+		}
+	}
+
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return ctxd.AddFields(ctx, fields...)
+}
+
+func (l *logger) incomingMetadataContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return l.metadataContext(ctx, md)
+}
+
+func (l *logger) outgoingMetadataContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	return l.metadataContext(ctx, md)
+}
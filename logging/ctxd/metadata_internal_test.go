@@ -0,0 +1,126 @@
+package ctxd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor_MetadataFields(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/grpctest.ItemService/GetItem",
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"x-request-id":  {"42"},
+		"x-tenant-id":   {"acme"},
+		"x-roles":       {"admin", "user"},
+		"authorization": {"Bearer token"},
+		"x-secret":      {"secret"},
+	})
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+
+	interceptor := UnaryServerInterceptor(logger,
+		WithMetadataFields("X-Request-Id", "x-roles", "authorization", "x-missing"),
+		WithMetadataField("x-tenant-id", "tenant"),
+		WithRedactor(NewRedactor()),
+	)
+
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		logger.Info(ctx, "handling call")
+
+		return 42, nil
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "handling call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.metadata.x-request-id": "42",
+    "grpc.metadata.x-roles": ["admin", "user"],
+    "grpc.metadata.authorization": "[REDACTED]",
+    "tenant": "acme"
+}`,
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.metadata.x-request-id": "42",
+    "grpc.metadata.x-roles": ["admin", "user"],
+    "grpc.metadata.authorization": "[REDACTED]",
+    "tenant": "acme",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+func TestUnaryClientInterceptor_MetadataFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "42")
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-request-id", "incoming"))
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	interceptor := UnaryClientInterceptor(logger,
+		WithMetadataField("x-request-id", "request_id"),
+		WithMetadataField("X-Request-Id", "request.id"),
+	)
+
+	err := interceptor(ctx, "/grpctest.ItemService/GetItem", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "request.id": "42",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestLogger_MetadataContext_NoMetadata(t *testing.T) {
+	t.Parallel()
+
+	l := defaultLogger(nil)
+	WithMetadataFields("x-request-id")(l)
+
+	ctx := context.Background()
+
+	assert.Equal(t, ctx, l.incomingMetadataContext(ctx))
+	assert.Equal(t, ctx, l.outgoingMetadataContext(ctx))
+}
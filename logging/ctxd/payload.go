@@ -0,0 +1,86 @@
+package ctxd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const truncatedPayloadSuffix = "...(truncated)"
+
+type payloadCtxKey struct{}
+
+// IsPayloadEntry tells whether the entry is a payload of WithPayloadLogging, not a finished call. The context must be
+// the one given to the MessageProducer.
+func IsPayloadEntry(ctx context.Context) bool {
+	_, ok := ctx.Value(payloadCtxKey{}).(struct{})
+
+	return ok
+}
+
+func (l *logger) shouldLogPayloadOf(ctx context.Context, fullMethod string) bool {
+	return l.shouldLogPayload != nil && l.shouldLogPayload(ctx, fullMethod)
+}
+
+// logPayload logs a payload with the context fields of the call. The context is marked, so the MessageProducer can tell
+// the payload entries from the finished calls, see IsPayloadEntry.
+func (l *logger) logPayload(ctx context.Context, msg string, field string, payload any) {
+	ctx = ctxd.AddFields(ctx, field, l.renderPayload(payload))
+	ctx = context.WithValue(ctx, payloadCtxKey{}, struct{}{})
+
+	l.Write(ctx, LogLevelDebug, msg, codes.OK, nil, 0)
+}
+
+// renderPayload renders the payload in JSON, using protojson for proto.Message. The sensitive values are redacted if
+// there is a redactor.
+func (l *logger) renderPayload(payload any) string {
+	var (
+		data []byte
+		desc protoreflect.MessageDescriptor
+		err  error
+	)
+
+	if m, ok := payload.(proto.Message); ok {
+		data, err = protojson.Marshal(m)
+		desc = m.ProtoReflect().Descriptor()
+	} else {
+		data, err = json.Marshal(payload)
+	}
+
+	if err != nil {
+		if l.redactor != nil {
+			return l.redactor.maskString(fmt.Sprintf("%+v", payload))
+		}
+
+		return fmt.Sprintf("%+v", payload)
+	}
+
+	if l.redactor != nil {
+		data = l.redactor.redactJSON(data, desc)
+	}
+
+	// protojson output is unstable on purpose, compact it to have the same output for the same payload.
+	var buf bytes.Buffer
+
+	if err := json.Compact(&buf, data); err == nil {
+		data = buf.Bytes()
+	}
+
+	return truncatePayload(string(data), l.maxPayloadSize)
+}
+
+func truncatePayload(s string, size int) string {
+	if size <= 0 || len(s) <= size {
+		return s
+	}
+
+	return strings.ToValidUTF8(s[:size], "") + truncatedPayloadSuffix
+}
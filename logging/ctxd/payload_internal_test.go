@@ -0,0 +1,268 @@
+package ctxd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLogger_RenderPayload(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario string
+		payload  any
+		maxSize  int
+		expected string
+	}{
+		{
+			scenario: "proto message",
+			payload:  wrapperspb.String("hello"),
+			expected: `"hello"`,
+		},
+		{
+			scenario: "proto message with fields",
+			payload: func() any {
+				s, err := structpb.NewStruct(map[string]any{"name": "john", "age": 42})
+				require.NoError(t, err)
+
+				return s
+			}(),
+			expected: `{"age":42,"name":"john"}`,
+		},
+		{
+			scenario: "not a proto message",
+			payload:  map[string]any{"id": 42},
+			expected: `{"id":42}`,
+		},
+		{
+			scenario: "not serializable",
+			payload:  func() {},
+			expected: `<ignore-diff>`,
+		},
+		{
+			scenario: "truncated",
+			payload:  wrapperspb.String("hello world"),
+			maxSize:  6,
+			expected: `"hello...(truncated)`,
+		},
+		{
+			scenario: "truncated at rune boundary",
+			payload:  "héllo",
+			maxSize:  3,
+			expected: `"h...(truncated)`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			l := defaultLogger(nil)
+			l.maxPayloadSize = tc.maxSize
+
+			actual := l.renderPayload(tc.payload)
+
+			if tc.expected != `<ignore-diff>` {
+				assert.Equal(t, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_PayloadLogging(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	interceptor := UnaryServerInterceptor(logger,
+		WithPayloadLogging(func(_ context.Context, fullMethod string) bool {
+			return fullMethod == "/grpctest.ItemService/GetItem"
+		}),
+	)
+
+	resp, err := interceptor(context.Background(), wrapperspb.String("request"), info, func(context.Context, any) (any, error) {
+		return wrapperspb.String("response"), nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "response", resp.(*wrapperspb.StringValue).GetValue())
+
+	expected := []string{
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "server request payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.request.content": "\"request\""
+}`,
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "server response payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.response.content": "\"response\""
+}`,
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+func TestUnaryServerInterceptor_PayloadLogging_MessageProducer(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	interceptor := UnaryServerInterceptor(logger,
+		WithPayloadLogging(func(context.Context, string) bool {
+			return true
+		}),
+		WithMessageProducer(func(ctx context.Context, msg string, code codes.Code, err error, duration time.Duration) (context.Context, string) {
+			if IsPayloadEntry(ctx) {
+				return ctx, "payload: " + msg
+			}
+
+			return DefaultMessageProducer(ctx, msg, code, err, duration)
+		}),
+	)
+
+	_, err := interceptor(context.Background(), wrapperspb.String("request"), info, func(context.Context, any) (any, error) {
+		return wrapperspb.String("response"), nil
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "payload: server request payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.request.content": "\"request\""
+}`,
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "payload: server response payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.response.content": "\"response\""
+}`,
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+func TestStreamServerInterceptor_PayloadLogging(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+	stream := &messageServerStream{serverStream: serverStream{context: context.Background()}}
+
+	interceptor := StreamServerInterceptor(logger,
+		WithDecider(func(string, error) bool {
+			return false
+		}),
+		WithPayloadLogging(func(context.Context, string) bool {
+			return true
+		}),
+	)
+
+	err := interceptor(nil, stream, info, func(_ any, stream grpc.ServerStream) error {
+		require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+		require.NoError(t, stream.SendMsg(wrapperspb.String("response")))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "server request payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.request.content": "\"request\""
+}`,
+		`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "server response payload logged",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.response.content": "\"response\""
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+// messageServerStream is a server stream that receives a "request" string value and accepts any message.
+type messageServerStream struct {
+	serverStream
+}
+
+func (s *messageServerStream) RecvMsg(m any) error {
+	m.(*wrapperspb.StringValue).Value = "request"
+
+	return nil
+}
+
+func (s *messageServerStream) SendMsg(any) error {
+	return nil
+}
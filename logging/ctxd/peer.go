@@ -0,0 +1,112 @@
+package ctxd
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// FieldPeerAddress is a context field for the address of the peer.
+	FieldPeerAddress = "peer.address"
+	// FieldPeerAuthType is a context field for the auth type of the connection, e.g. "tls" or "alts".
+	FieldPeerAuthType = "peer.auth_type"
+	// FieldPeerSubject is a context field for the subject of the TLS certificate of the peer.
+	FieldPeerSubject = "peer.subject"
+	// FieldPeerSAN is a context field for the subject alternative names of the TLS certificate of the peer.
+	FieldPeerSAN = "peer.san"
+	// FieldPeerServiceAccount is a context field for the service account of the peer, with ALTS.
+	FieldPeerServiceAccount = "peer.service_account"
+	// FieldTarget is a context field for the target of the client connection.
+	FieldTarget = "grpc.target"
+)
+
+// WithPeerFields adds the information of the peer to the context fields: the address, the auth type, and the
+// identity of the peer, i.e. the certificate subject and the subject alternative names with TLS, or the service account
+// with ALTS. The client interceptors also add the target of the connection, and the resolved address of the server
+// once it is known.
+func WithPeerFields() Option {
+	return func(l *logger) {
+		l.peerFields = true
+	}
+}
+
+// peerContext adds the information of the peer in the context to the context fields.
+func (l *logger) peerContext(ctx context.Context) context.Context {
+	if !l.peerFields {
+		return ctx
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return l.addPeerFields(ctx, p)
+}
+
+func (l *logger) addPeerFields(ctx context.Context, p *peer.Peer) context.Context {
+	if !l.peerFields || p == nil {
+		return ctx
+	}
+
+	var fields []any
+
+	if p.Addr != nil {
+		fields = append(fields, FieldPeerAddress, p.Addr.String())
+	}
+
+	if p.AuthInfo != nil {
+		fields = append(fields, FieldPeerAuthType, p.AuthInfo.AuthType())
+	}
+
+	switch info := p.AuthInfo.(type) {
+	case credentials.TLSInfo:
+		if certs := info.State.PeerCertificates; len(certs) > 0 {
+			fields = append(fields, FieldPeerSubject, certs[0].Subject.String())
+
+			if san := subjectAltNames(certs[0]); len(san) > 0 {
+				fields = append(fields, FieldPeerSAN, san)
+			}
+		}
+
+	case interface{ PeerServiceAccount() string }: // ALTS.
+		fields = append(fields, FieldPeerServiceAccount, info.PeerServiceAccount())
+	}
+
+	if len(fields) == 0 {
+		return ctx
+	}
+
+	return ctxd.AddFields(ctx, fields...)
+}
+
+// targetContext adds the target of the client connection to the context fields.
+func (l *logger) targetContext(ctx context.Context, cc *grpc.ClientConn) context.Context {
+	if !l.peerFields || cc == nil {
+		return ctx
+	}
+
+	return ctxd.AddFields(ctx, FieldTarget, cc.Target())
+}
+
+func subjectAltNames(cert *x509.Certificate) []string {
+	san := make([]string, 0, len(cert.DNSNames)+len(cert.EmailAddresses)+len(cert.IPAddresses)+len(cert.URIs))
+
+	san = append(san, cert.DNSNames...)
+	san = append(san, cert.EmailAddresses...)
+
+	for _, ip := range cert.IPAddresses {
+		san = append(san, ip.String())
+	}
+
+	for _, u := range cert.URIs {
+		san = append(san, u.String())
+	}
+
+	return san
+}
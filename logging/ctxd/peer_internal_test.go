@@ -0,0 +1,307 @@
+package ctxd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const echoMethod = "/grpctest.EchoService/Echo"
+
+var echoStreamDesc = &grpc.StreamDesc{
+	StreamName:    "Echo",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+type altsAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (altsAuthInfo) AuthType() string {
+	return "alts"
+}
+
+func (altsAuthInfo) PeerServiceAccount() string {
+	return "client@example.iam.gserviceaccount.com"
+}
+
+func TestUnaryServerInterceptor_PeerFields(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.UnaryServerInfo{
+		FullMethod: "/grpctest.ItemService/GetItem",
+	}
+
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4242}
+
+	testCases := []struct {
+		scenario           string
+		context            context.Context
+		options            []Option
+		expectedLogMessage string
+	}{
+		{
+			scenario: "disabled",
+			context:  peer.NewContext(context.Background(), &peer.Peer{Addr: addr}),
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario: "no peer",
+			context:  context.Background(),
+			options:  []Option{WithPeerFields()},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario: "tls",
+			context: peer.NewContext(context.Background(), &peer.Peer{
+				Addr: addr,
+				AuthInfo: credentials.TLSInfo{
+					State: tls.ConnectionState{
+						PeerCertificates: []*x509.Certificate{{
+							Subject:        pkix.Name{CommonName: "client", Organization: []string{"Acme"}},
+							DNSNames:       []string{"client.example.com"},
+							EmailAddresses: []string{"client@example.com"},
+							IPAddresses:    []net.IP{net.IPv4(10, 0, 0, 1)},
+							URIs:           []*url.URL{{Scheme: "spiffe", Host: "example.com", Path: "/client"}},
+						}},
+					},
+				},
+			}),
+			options: []Option{WithPeerFields()},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "peer.address": "10.0.0.1:4242",
+    "peer.auth_type": "tls",
+    "peer.subject": "CN=client,O=Acme",
+    "peer.san": ["client.example.com", "client@example.com", "10.0.0.1", "spiffe://example.com/client"],
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario: "alts",
+			context: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     addr,
+				AuthInfo: altsAuthInfo{},
+			}),
+			options: []Option{WithPeerFields()},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "peer.address": "10.0.0.1:4242",
+    "peer.auth_type": "alts",
+    "peer.service_account": "client@example.iam.gserviceaccount.com",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+
+			_, err := UnaryServerInterceptor(logger, tc.options...)(tc.context, nil, info, func(context.Context, any) (any, error) {
+				return 42, nil
+			})
+			require.NoError(t, err)
+
+			assertLogMessage(t, tc.expectedLogMessage, buf.String())
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_PeerFields(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	conn := newEchoConn(t, grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(logger, WithPeerFields())))
+
+	err := conn.Invoke(context.Background(), echoMethod, wrapperspb.String("hello"), new(wrapperspb.StringValue))
+	require.NoError(t, err)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.EchoService",
+    "grpc.method": "Echo",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.target": "passthrough:///bufnet",
+    "peer.address": "bufconn",
+    "peer.auth_type": "insecure",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamClientInterceptor_PeerFields(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	conn := newEchoConn(t, grpc.WithChainStreamInterceptor(StreamClientInterceptor(logger, WithPeerFields())))
+
+	stream, err := conn.NewStream(context.Background(), echoStreamDesc, echoMethod)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.SendMsg(wrapperspb.String("hello")))
+	require.NoError(t, stream.CloseSend())
+	require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+	require.ErrorIs(t, stream.RecvMsg(new(wrapperspb.StringValue)), io.EOF)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.EchoService",
+    "grpc.method": "Echo",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.target": "passthrough:///bufnet",
+    "peer.address": "bufconn",
+    "peer.auth_type": "insecure",
+    "grpc.msg.sent": 1,
+    "grpc.msg.received": 1,
+    "grpc.bytes.sent": 7,
+    "grpc.bytes.received": 7,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamClientInterceptor_PeerFields_KeepsRetries(t *testing.T) {
+	t.Parallel()
+
+	logger, _ := newCtxdLogger(LogLevelDebug)
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &noContextClientStream{t: t}, nil
+	}
+
+	stream, err := StreamClientInterceptor(logger, WithPeerFields())(context.Background(), echoStreamDesc, nil, echoMethod, streamer)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+}
+
+// noContextClientStream fails the test when its context is read, because it commits the stream and disables the
+// retries.
+type noContextClientStream struct {
+	clientStream
+
+	t *testing.T
+}
+
+func (s *noContextClientStream) Context() context.Context {
+	s.t.Error("the context of the stream must not be read")
+
+	return context.Background()
+}
+
+// newEchoConn starts a server that echoes every wrapperspb.StringValue it receives, and connects to it.
+func newEchoConn(t *testing.T, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	buf := bufconn.Listen(1024 * 1024)
+
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		for {
+			in := new(wrapperspb.StringValue)
+
+			if err := stream.RecvMsg(in); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+
+				return err
+			}
+
+			if err := stream.SendMsg(in); err != nil {
+				return err
+			}
+		}
+	}))
+
+	go func() {
+		_ = srv.Serve(buf) //nolint: errcheck
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return buf.Dial()
+		}),
+	}, opts...)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close() //nolint: errcheck
+	})
+
+	return conn
+}
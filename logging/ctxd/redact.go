@@ -0,0 +1,356 @@
+package ctxd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// DefaultRedactionMask is the default mask of the redacted values.
+const DefaultRedactionMask = "[REDACTED]"
+
+// RedactorOption configures a Redactor.
+type RedactorOption func(r *Redactor)
+
+// Redactor masks the sensitive values of the payloads and the metadata before they are logged.
+//
+// A payload field is redacted when:
+//   - its proto field name, or its JSON key for the payloads that are not proto.Message, is in RedactFields.
+//   - its JSON path, e.g. "user.password", is in RedactPaths. The items of the arrays share the path of the array.
+//   - it has the standard debug_redact field option.
+//   - it has one of the custom field options in RedactSensitiveFields set to true.
+//
+// The messages packed in a google.protobuf.Any are redacted with their own descriptor, resolved from the global registry.
+// An Any whose type could not be resolved is masked entirely.
+//
+// The metadata keys in RedactMetadataKeys are redacted, "authorization" and "cookie" by default.
+type Redactor struct {
+	fields       map[string]struct{}
+	paths        map[string]struct{}
+	sensitive    []protoreflect.ExtensionType
+	metadataKeys map[string]struct{}
+
+	mask    string
+	hashKey []byte
+	hash    bool
+}
+
+// NewRedactor creates a new redactor.
+func NewRedactor(opts ...RedactorOption) *Redactor {
+	r := &Redactor{
+		fields: make(map[string]struct{}),
+		paths:  make(map[string]struct{}),
+		metadataKeys: map[string]struct{}{
+			"authorization": {},
+			"cookie":        {},
+		},
+		mask: DefaultRedactionMask,
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// RedactFields redacts the payload fields by their proto field names, or their JSON keys.
+func RedactFields(names ...string) RedactorOption {
+	return func(r *Redactor) {
+		for _, n := range names {
+			r.fields[n] = struct{}{}
+		}
+	}
+}
+
+// RedactPaths redacts the payload fields by their JSON paths, e.g. "user.password".
+func RedactPaths(paths ...string) RedactorOption {
+	return func(r *Redactor) {
+		for _, p := range paths {
+			r.paths[strings.TrimPrefix(p, "$.")] = struct{}{}
+		}
+	}
+}
+
+// RedactSensitiveFields redacts the payload fields that have one of the given boolean field options set to true, e.g.
+// a custom `(sensitive) = true` option.
+func RedactSensitiveFields(options ...protoreflect.ExtensionType) RedactorOption {
+	return func(r *Redactor) {
+		r.sensitive = append(r.sensitive, options...)
+	}
+}
+
+// RedactMetadataKeys redacts the values of the metadata keys, in addition to the default ones.
+func RedactMetadataKeys(keys ...string) RedactorOption {
+	return func(r *Redactor) {
+		for _, k := range keys {
+			r.metadataKeys[strings.ToLower(k)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactionMask sets the mask of the redacted values. The default is DefaultRedactionMask.
+func WithRedactionMask(mask string) RedactorOption {
+	return func(r *Redactor) {
+		r.mask = mask
+	}
+}
+
+// WithRedactionHashing replaces the redacted values with their HMAC-SHA256 hash, e.g. "sha256:5d41402abc4b2a76", so
+// the same values can be correlated without being revealed.
+func WithRedactionHashing(key []byte) RedactorOption {
+	return func(r *Redactor) {
+		r.hash = true
+		r.hashKey = key
+	}
+}
+
+// RedactMetadata returns a copy of the metadata with the sensitive values masked.
+func (r *Redactor) RedactMetadata(md metadata.MD) metadata.MD {
+	redacted := md.Copy()
+
+	for k, values := range redacted {
+		if !r.isSensitiveMetadataKey(k) {
+			continue
+		}
+
+		for i, v := range values {
+			values[i] = r.maskString(v)
+		}
+	}
+
+	return redacted
+}
+
+func (r *Redactor) isSensitiveMetadataKey(key string) bool {
+	_, ok := r.metadataKeys[strings.ToLower(key)]
+
+	return ok
+}
+
+// redactJSON redacts the payload rendered in JSON. The descriptor is the descriptor of the payload if it is a
+// proto.Message, or nil.
+func (r *Redactor) redactJSON(data []byte, desc protoreflect.MessageDescriptor) []byte {
+	var v any
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(&v); err != nil {
+		// Never leak what could not be inspected.
+		return r.maskJSON(data)
+	}
+
+	v = r.redactMessage(v, messageDescriptor(desc), "")
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return r.maskJSON(data)
+	}
+
+	return redacted
+}
+
+func (r *Redactor) redactMessage(v any, desc protoreflect.MessageDescriptor, path string) any {
+	if isAny(desc) {
+		return r.redactAny(v, path)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			childPath := joinPath(path, k)
+
+			var fd protoreflect.FieldDescriptor
+
+			if desc != nil {
+				if fd = desc.Fields().ByJSONName(k); fd == nil {
+					fd = desc.Fields().ByName(protoreflect.Name(k))
+				}
+			}
+
+			if r.isSensitiveField(k, fd, childPath) {
+				v[k] = r.maskValue(child)
+
+				continue
+			}
+
+			v[k] = r.redactField(child, fd, childPath)
+		}
+
+	case []any:
+		for i, child := range v {
+			v[i] = r.redactMessage(child, desc, path)
+		}
+	}
+
+	return v
+}
+
+func (r *Redactor) redactField(v any, fd protoreflect.FieldDescriptor, path string) any {
+	if fd == nil {
+		return r.redactMessage(v, nil, path)
+	}
+
+	if fd.IsMap() {
+		entries, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+
+		valueDesc := messageDescriptor(fd.MapValue().Message())
+
+		for k, entry := range entries {
+			entryPath := joinPath(path, k)
+
+			if r.isSensitiveField(k, nil, entryPath) {
+				entries[k] = r.maskValue(entry)
+
+				continue
+			}
+
+			entries[k] = r.redactMessage(entry, valueDesc, entryPath)
+		}
+
+		return entries
+	}
+
+	return r.redactMessage(v, messageDescriptor(fd.Message()), path)
+}
+
+// redactAny redacts a google.protobuf.Any with the descriptor of the packed message, which is resolved from the @type
+// with the global registry, as protojson does. The whole value is masked if the type could not be resolved.
+func (r *Redactor) redactAny(v any, path string) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return r.maskValue(v)
+	}
+
+	typeURL, _ := m["@type"].(string) //nolint: errcheck
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return r.maskValue(v)
+	}
+
+	packed := mt.Descriptor()
+
+	// The well-known types, Any included, are packed in a "value" key, with their special JSON representation.
+	switch {
+	case isAny(packed):
+		m["value"] = r.redactAny(m["value"], path)
+
+	case messageDescriptor(packed) == nil:
+		m["value"] = r.redactMessage(m["value"], nil, path)
+
+	default:
+		return r.redactMessage(m, packed, path)
+	}
+
+	return m
+}
+
+func (r *Redactor) isSensitiveField(key string, fd protoreflect.FieldDescriptor, path string) bool {
+	if _, ok := r.fields[key]; ok {
+		return true
+	}
+
+	if _, ok := r.paths[path]; ok {
+		return true
+	}
+
+	if fd == nil {
+		return false
+	}
+
+	if _, ok := r.fields[string(fd.Name())]; ok {
+		return true
+	}
+
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	if !ok || opts == nil {
+		return false
+	}
+
+	if opts.GetDebugRedact() {
+		return true
+	}
+
+	for _, ext := range r.sensitive {
+		if sensitive, ok := proto.GetExtension(opts, ext).(bool); ok && sensitive {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Redactor) maskValue(v any) any {
+	if !r.hash {
+		return r.mask
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return r.mask
+	}
+
+	return r.hashOf(data)
+}
+
+func (r *Redactor) maskString(s string) string {
+	if !r.hash {
+		return r.mask
+	}
+
+	return r.hashOf([]byte(s))
+}
+
+func (r *Redactor) maskJSON(data []byte) []byte {
+	masked, _ := json.Marshal(r.maskString(string(data))) //nolint: errcheck,errchkjson
+
+	return masked
+}
+
+func (r *Redactor) hashOf(data []byte) string {
+	h := hmac.New(sha256.New, r.hashKey)
+	_, _ = h.Write(data)
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// messageDescriptor returns the descriptor that matches the JSON representation of the message. The well-known types
+// have a special JSON representation, so their descriptors are ignored, except google.protobuf.Any whose packed message
+// has to be redacted.
+func messageDescriptor(desc protoreflect.MessageDescriptor) protoreflect.MessageDescriptor {
+	if isAny(desc) {
+		return desc
+	}
+
+	if desc == nil || desc.ParentFile() == nil || desc.ParentFile().Package() == "google.protobuf" {
+		return nil
+	}
+
+	return desc
+}
+
+func isAny(desc protoreflect.MessageDescriptor) bool {
+	return desc != nil && desc.FullName() == "google.protobuf.Any"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
@@ -0,0 +1,304 @@
+package ctxd
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestRedactor_Payload(t *testing.T) {
+	t.Parallel()
+
+	sensitive, user := newRedactTestUser(t)
+
+	testCases := []struct {
+		scenario string
+		redactor *Redactor
+		payload  any
+		expected string
+	}{
+		{
+			scenario: "debug_redact only",
+			redactor: NewRedactor(),
+			payload:  user,
+			expected: `{"address":{"secretCode":"1234","street":"Main St"},"apiKey":"key","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"[REDACTED]","tokens":["t1","t2"]}`,
+		},
+		{
+			scenario: "sensitive option",
+			redactor: NewRedactor(RedactSensitiveFields(sensitive)),
+			payload:  user,
+			expected: `{"address":{"secretCode":"1234","street":"Main St"},"apiKey":"[REDACTED]","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"[REDACTED]","tokens":["t1","t2"]}`,
+		},
+		{
+			scenario: "proto field names",
+			redactor: NewRedactor(RedactFields("secret_code", "tokens")),
+			payload:  user,
+			expected: `{"address":{"secretCode":"[REDACTED]","street":"Main St"},"apiKey":"key","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"[REDACTED]","tokens":"[REDACTED]"}`,
+		},
+		{
+			scenario: "json paths",
+			redactor: NewRedactor(RedactPaths("$.address.street", "labels.secret")),
+			payload:  user,
+			expected: `{"address":{"secretCode":"1234","street":"[REDACTED]"},"apiKey":"key","labels":{"env":"prod","secret":"[REDACTED]"},"name":"john","password":"[REDACTED]","tokens":["t1","t2"]}`,
+		},
+		{
+			scenario: "custom mask",
+			redactor: NewRedactor(WithRedactionMask("***")),
+			payload:  user,
+			expected: `{"address":{"secretCode":"1234","street":"Main St"},"apiKey":"key","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"***","tokens":["t1","t2"]}`,
+		},
+		{
+			scenario: "hashing",
+			redactor: NewRedactor(RedactFields("name"), WithRedactionHashing([]byte("key"))),
+			payload:  map[string]any{"items": []any{map[string]any{"name": "john"}, map[string]any{"name": "john"}}},
+			expected: `{"items":[{"name":"sha256:b7dd55463e9c1223"},{"name":"sha256:b7dd55463e9c1223"}]}`,
+		},
+		{
+			scenario: "not a proto message",
+			redactor: NewRedactor(RedactFields("password"), RedactPaths("user.token")),
+			payload:  map[string]any{"user": map[string]any{"password": "secret", "token": "abc", "id": 42}},
+			expected: `{"user":{"id":42,"password":"[REDACTED]","token":"[REDACTED]"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			l := defaultLogger(nil)
+			l.redactor = tc.redactor
+
+			assert.Equal(t, tc.expected, l.renderPayload(tc.payload))
+		})
+	}
+}
+
+func TestRedactor_Payload_Any(t *testing.T) {
+	t.Parallel()
+
+	_, user := newRedactTestUser(t)
+
+	packed, err := anypb.New(user)
+	require.NoError(t, err)
+
+	nested, err := anypb.New(packed)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		scenario string
+		payload  proto.Message
+		expected string
+	}{
+		{
+			scenario: "packed message",
+			payload:  packed,
+			expected: `{"@type":"type.googleapis.com/grpctest.User","address":{"secretCode":"[REDACTED]","street":"Main St"},"apiKey":"key","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"[REDACTED]","tokens":["t1","t2"]}`,
+		},
+		{
+			scenario: "nested any",
+			payload:  nested,
+			expected: `{"@type":"type.googleapis.com/google.protobuf.Any","value":{"@type":"type.googleapis.com/grpctest.User","address":{"secretCode":"[REDACTED]","street":"Main St"},"apiKey":"key","labels":{"env":"prod","secret":"s3cr3t"},"name":"john","password":"[REDACTED]","tokens":["t1","t2"]}}`,
+		},
+		{
+			scenario: "packed well-known type",
+			payload: func() proto.Message {
+				m, err := anypb.New(structpb.NewStringValue("secret"))
+				require.NoError(t, err)
+
+				return m
+			}(),
+			expected: `{"@type":"type.googleapis.com/google.protobuf.Value","value":"secret"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			l := defaultLogger(nil)
+			l.redactor = NewRedactor(RedactFields("secret_code"))
+
+			assert.Equal(t, tc.expected, l.renderPayload(tc.payload))
+		})
+	}
+}
+
+func TestRedactor_RedactJSON_UnresolvedAny(t *testing.T) {
+	t.Parallel()
+
+	r := NewRedactor()
+	desc := (&anypb.Any{}).ProtoReflect().Descriptor()
+
+	actual := r.redactJSON([]byte(`{"@type":"type.googleapis.com/grpctest.Unknown","password":"secret"}`), desc)
+
+	assert.JSONEq(t, `"[REDACTED]"`, string(actual))
+}
+
+func TestRedactor_Payload_NotSerializable(t *testing.T) {
+	t.Parallel()
+
+	l := defaultLogger(nil)
+	l.redactor = NewRedactor()
+
+	assert.Equal(t, DefaultRedactionMask, l.renderPayload(func() {}))
+}
+
+func TestRedactor_RedactMetadata(t *testing.T) {
+	t.Parallel()
+
+	md := metadata.Pairs(
+		"authorization", "Bearer token",
+		"x-api-key", "key",
+		"x-request-id", "42",
+	)
+
+	r := NewRedactor(RedactMetadataKeys("X-Api-Key"))
+
+	expected := metadata.Pairs(
+		"authorization", DefaultRedactionMask,
+		"x-api-key", DefaultRedactionMask,
+		"x-request-id", "42",
+	)
+
+	assert.Equal(t, expected, r.RedactMetadata(md))
+	assert.Equal(t, []string{"Bearer token"}, md.Get("authorization"), "the metadata must not be modified")
+}
+
+var registerRedactTestTypes sync.Once
+
+// newRedactTestUser builds a dynamic message with a `password` field that has the debug_redact option and an
+// `api_key` field that has a custom `(sensitive)` option.
+func newRedactTestUser(t *testing.T) (protoreflect.ExtensionType, proto.Message) {
+	t.Helper()
+
+	files := new(protoregistry.Files)
+
+	require.NoError(t, files.RegisterFile(descriptorpb.File_google_protobuf_descriptor_proto))
+
+	optionFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("grpctest/options.proto"),
+		Package:    proto.String("grpctest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("sensitive"),
+			Number:   proto.Int32(50000),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(),
+			Extendee: proto.String(".google.protobuf.FieldOptions"),
+			JsonName: proto.String("sensitive"),
+		}},
+	}, files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(optionFile))
+
+	sensitive := dynamicpb.NewExtensionType(optionFile.Extensions().Get(0))
+
+	sensitiveOptions := &descriptorpb.FieldOptions{}
+	proto.SetExtension(sensitiveOptions, sensitive, true)
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, opts ...func(*descriptorpb.FieldDescriptorProto)) *descriptorpb.FieldDescriptorProto {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:   typ.Enum(),
+		}
+
+		for _, o := range opts {
+			o(fd)
+		}
+
+		return fd
+	}
+
+	typeName := func(name string) func(*descriptorpb.FieldDescriptorProto) {
+		return func(fd *descriptorpb.FieldDescriptorProto) {
+			fd.TypeName = proto.String(name)
+		}
+	}
+
+	repeated := func(fd *descriptorpb.FieldDescriptorProto) {
+		fd.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	}
+
+	userFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("grpctest/user.proto"),
+		Package:    proto.String("grpctest"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/descriptor.proto", "grpctest/options.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					field("password", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, func(fd *descriptorpb.FieldDescriptorProto) {
+						fd.Options = &descriptorpb.FieldOptions{DebugRedact: proto.Bool(true)}
+					}),
+					field("api_key", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, func(fd *descriptorpb.FieldDescriptorProto) {
+						fd.Options = sensitiveOptions
+					}),
+					field("tokens", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, repeated),
+					field("address", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, typeName(".grpctest.Address")),
+					field("labels", 6, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, typeName(".grpctest.User.LabelsEntry"), repeated),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+			{
+				Name: proto.String("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("street", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					field("secret_code", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				},
+			},
+		},
+	}, files)
+	require.NoError(t, err)
+
+	userDesc := userFile.Messages().ByName("User")
+	addressDesc := userFile.Messages().ByName("Address")
+
+	// The packed messages of google.protobuf.Any are resolved with the global registry.
+	registerRedactTestTypes.Do(func() {
+		require.NoError(t, protoregistry.GlobalTypes.RegisterMessage(dynamicpb.NewMessageType(userDesc)))
+		require.NoError(t, protoregistry.GlobalTypes.RegisterMessage(dynamicpb.NewMessageType(addressDesc)))
+	})
+
+	address := dynamicpb.NewMessage(addressDesc)
+	address.Set(addressDesc.Fields().ByName("street"), protoreflect.ValueOfString("Main St"))
+	address.Set(addressDesc.Fields().ByName("secret_code"), protoreflect.ValueOfString("1234"))
+
+	user := dynamicpb.NewMessage(userDesc)
+	user.Set(userDesc.Fields().ByName("name"), protoreflect.ValueOfString("john"))
+	user.Set(userDesc.Fields().ByName("password"), protoreflect.ValueOfString("secret"))
+	user.Set(userDesc.Fields().ByName("api_key"), protoreflect.ValueOfString("key"))
+	user.Set(userDesc.Fields().ByName("address"), protoreflect.ValueOfMessage(address))
+
+	tokens := user.Mutable(userDesc.Fields().ByName("tokens")).List()
+	tokens.Append(protoreflect.ValueOfString("t1"))
+	tokens.Append(protoreflect.ValueOfString("t2"))
+
+	labels := user.Mutable(userDesc.Fields().ByName("labels")).Map()
+	labels.Set(protoreflect.ValueOfString("env").MapKey(), protoreflect.ValueOfString("prod"))
+	labels.Set(protoreflect.ValueOfString("secret").MapKey(), protoreflect.ValueOfString("s3cr3t"))
+
+	return sensitive, user
+}
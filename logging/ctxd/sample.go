@@ -0,0 +1,191 @@
+package ctxd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/codes"
+)
+
+// FieldSuppressed is a context field for the number of log entries that are suppressed by the sampling.
+const FieldSuppressed = "grpc.suppressed"
+
+// WithSampling logs only 1 in every n successful calls of a method, the first one included. The calls that end with an
+// error, and the entries at LogLevelImportant or above, e.g. the slow calls, are always logged.
+func WithSampling(n int) Option {
+	return func(l *logger) {
+		l.sampler().every = n
+	}
+}
+
+// WithRateLimit limits the log entries of the successful calls of each method with a token bucket, that holds at most
+// burst tokens, at least 1, and is refilled at limit tokens per second. The calls that end with an error, and the
+// entries at LogLevelImportant or above, e.g. the slow calls, are never limited.
+func WithRateLimit(limit float64, burst int) Option {
+	return func(l *logger) {
+		s := l.sampler()
+
+		s.limit = limit
+		s.burst = float64(max(burst, 1))
+	}
+}
+
+type sampleState struct {
+	calls      uint64
+	tokens     float64
+	updatedAt  time.Time
+	suppressed int
+	level      LogLevel
+}
+
+// suppressedEntries is the summary of the entries of a method that were not logged.
+type suppressedEntries struct {
+	fullMethod string
+	level      LogLevel
+	count      int
+}
+
+// sampler decides which log entries of the finished calls are logged. The entries that are not logged are counted,
+// and reported in a summary line before the next entry of the same method, or after the flush interval.
+type sampler struct {
+	every         int
+	limit         float64
+	burst         float64
+	now           func() time.Time
+	flushInterval time.Duration
+
+	mu             sync.Mutex
+	states         map[string]*sampleState
+	flushScheduled bool
+}
+
+func (l *logger) sampler() *sampler {
+	if l.sampling == nil {
+		l.sampling = &sampler{
+			now:           time.Now,
+			flushInterval: time.Second,
+			states:        make(map[string]*sampleState),
+		}
+	}
+
+	return l.sampling
+}
+
+// sample checks whether the entry of a finished call is logged, and logs the summary of the suppressed entries if any.
+func (l *logger) sample(fullMethod string, level LogLevel, code codes.Code) bool {
+	if l.sampling == nil || code != codes.OK || level >= LogLevelImportant {
+		return true
+	}
+
+	allowed, suppressed, scheduleFlush := l.sampling.allow(fullMethod, level)
+
+	if scheduleFlush {
+		time.AfterFunc(l.sampling.flushInterval, l.flushSuppressed)
+	}
+
+	if !allowed {
+		return false
+	}
+
+	if suppressed.count > 0 {
+		l.writeSuppressed(suppressed)
+	}
+
+	return true
+}
+
+// flushSuppressed logs the summary of the entries that were suppressed since the last logged entry of each method.
+func (l *logger) flushSuppressed() {
+	for _, e := range l.sampling.drain() {
+		l.writeSuppressed(e)
+	}
+}
+
+// writeSuppressed logs the summary of the suppressed entries. The summary has only the fields of the method, not the
+// fields of a call.
+func (l *logger) writeSuppressed(e suppressedEntries) {
+	ctx := ctxd.AddFields(context.Background(),
+		FieldSystem, "grpc",
+		FieldKind, l.kind,
+		FieldService, path.Dir(e.fullMethod)[1:],
+		FieldMethod, path.Base(e.fullMethod),
+		FieldCode, codes.OK,
+		FieldSuppressed, e.count,
+	)
+
+	l.write(ctx, e.level, fmt.Sprintf("%d similar entries suppressed", e.count))
+}
+
+// allow checks whether an entry is logged. If so, it also returns the entries that were suppressed since the last
+// logged entry. It also tells whether a flush of the suppressed entries has to be scheduled.
+func (s *sampler) allow(fullMethod string, level LogLevel) (bool, suppressedEntries, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	state, ok := s.states[fullMethod]
+	if !ok {
+		state = &sampleState{tokens: s.burst, updatedAt: now}
+		s.states[fullMethod] = state
+	}
+
+	state.calls++
+
+	allowed := true
+
+	if s.every > 1 && (state.calls-1)%uint64(s.every) != 0 {
+		allowed = false
+	}
+
+	if allowed && s.limit > 0 {
+		state.tokens = min(s.burst, state.tokens+now.Sub(state.updatedAt).Seconds()*s.limit)
+		state.updatedAt = now
+
+		if state.tokens < 1 {
+			allowed = false
+		} else {
+			state.tokens--
+		}
+	}
+
+	if !allowed {
+		state.suppressed++
+		state.level = level
+
+		scheduleFlush := !s.flushScheduled
+		s.flushScheduled = true
+
+		return false, suppressedEntries{}, scheduleFlush
+	}
+
+	suppressed := suppressedEntries{fullMethod: fullMethod, level: state.level, count: state.suppressed}
+	state.suppressed = 0
+
+	return true, suppressed, false
+}
+
+// drain returns and resets the suppressed entries of all the methods.
+func (s *sampler) drain() []suppressedEntries {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flushScheduled = false
+
+	var entries []suppressedEntries
+
+	for fullMethod, state := range s.states {
+		if state.suppressed == 0 {
+			continue
+		}
+
+		entries = append(entries, suppressedEntries{fullMethod: fullMethod, level: state.level, count: state.suppressed})
+		state.suppressed = 0
+	}
+
+	return entries
+}
@@ -0,0 +1,264 @@
+package ctxd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/zapctxd"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSampler_Every(t *testing.T) {
+	t.Parallel()
+
+	l := defaultLogger(nil)
+	WithSampling(3)(l)
+
+	var actual []bool
+
+	for range 7 {
+		allowed, _, _ := l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+
+		actual = append(actual, allowed)
+	}
+
+	assert.Equal(t, []bool{true, false, false, true, false, false, true}, actual)
+
+	allowed, suppressed, _ := l.sampling.allow("/grpctest.ItemService/ListItems", LogLevelInfo)
+
+	assert.True(t, allowed, "the methods are sampled separately")
+	assert.Zero(t, suppressed.count)
+}
+
+func TestSampler_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	l := defaultLogger(nil)
+	WithRateLimit(2, 2)(l)
+
+	l.sampling.now = func() time.Time {
+		return now
+	}
+
+	assertAllowed := func(expected bool, expectedSuppressed int) {
+		t.Helper()
+
+		allowed, suppressed, _ := l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+
+		assert.Equal(t, expected, allowed)
+		assert.Equal(t, expectedSuppressed, suppressed.count)
+	}
+
+	// The bucket is full at the beginning.
+	assertAllowed(true, 0)
+	assertAllowed(true, 0)
+	assertAllowed(false, 0)
+	assertAllowed(false, 0)
+
+	// 1 token is refilled in 500ms.
+	now = now.Add(500 * time.Millisecond)
+
+	assertAllowed(true, 2)
+	assertAllowed(false, 0)
+
+	// The bucket never holds more than the burst.
+	now = now.Add(time.Hour)
+
+	assertAllowed(true, 1)
+	assertAllowed(true, 0)
+	assertAllowed(false, 0)
+}
+
+func TestSampler_RateLimit_ZeroBurst(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	l := defaultLogger(nil)
+	WithRateLimit(1, 0)(l)
+
+	l.sampling.now = func() time.Time {
+		return now
+	}
+
+	allowed, _, _ := l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+	assert.True(t, allowed, "the burst is at least 1")
+
+	allowed, _, _ = l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+	assert.False(t, allowed)
+
+	now = now.Add(time.Second)
+
+	allowed, _, _ = l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+	assert.True(t, allowed)
+}
+
+func TestSampler_FlushSchedule(t *testing.T) {
+	t.Parallel()
+
+	l := defaultLogger(nil)
+	WithSampling(10)(l)
+
+	allow := func() (bool, bool) {
+		allowed, _, scheduleFlush := l.sampling.allow("/grpctest.ItemService/GetItem", LogLevelInfo)
+
+		return allowed, scheduleFlush
+	}
+
+	allowed, scheduleFlush := allow()
+	assert.True(t, allowed)
+	assert.False(t, scheduleFlush)
+
+	_, scheduleFlush = allow()
+	assert.True(t, scheduleFlush, "the first suppressed entry schedules a flush")
+
+	_, scheduleFlush = allow()
+	assert.False(t, scheduleFlush, "the flush is already scheduled")
+
+	expected := []suppressedEntries{{fullMethod: "/grpctest.ItemService/GetItem", level: LogLevelInfo, count: 2}}
+
+	assert.Equal(t, expected, l.sampling.drain())
+	assert.Empty(t, l.sampling.drain())
+
+	_, scheduleFlush = allow()
+	assert.True(t, scheduleFlush, "a new flush is scheduled after the drain")
+}
+
+func TestUnaryServerInterceptor_Sampling(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	interceptor := UnaryServerInterceptor(logger, WithSampling(10))
+
+	handle := func(err error) {
+		_, _ = interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) { //nolint: errcheck
+			return nil, err
+		})
+	}
+
+	for range 3 {
+		handle(nil)
+	}
+
+	handle(status.Error(codes.Internal, "internal error"))
+
+	for range 8 {
+		handle(nil)
+	}
+
+	expected := []string{
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		`{
+    "level": "error",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "Internal",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = Internal desc = internal error"
+}`,
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "9 similar entries suppressed",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.code": "OK",
+    "grpc.suppressed": 9
+}`,
+		`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+func TestUnaryServerInterceptor_RateLimit_Errors(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	interceptor := UnaryServerInterceptor(logger, WithRateLimit(0.0001, 1))
+
+	for range 5 {
+		_, _ = interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) { //nolint: errcheck
+			return nil, status.Error(codes.NotFound, "not found")
+		})
+	}
+
+	assert.Equal(t, 5, countLogMessages(filterLogMessages(buf.String(), `"grpc.code":"NotFound"`)), "the errors are never limited")
+}
+
+func TestUnaryServerInterceptor_Sampling_Flush(t *testing.T) {
+	t.Parallel()
+
+	buf := new(syncBuffer)
+	logger := zapctxd.New(zapctxd.Config{Level: levelToZapLevel(LogLevelInfo), Output: buf})
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	// The fields of the calls are not in the summary.
+	ctx := ctxd.AddFields(context.Background(), "request.id", "42")
+
+	l := newServerLogger(logger, WithSampling(10))
+	l.sampling.flushInterval = 10 * time.Millisecond
+
+	for range 3 {
+		l.writeCall(ctx, info.FullMethod, "finished unary call", nil, time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "2 similar entries suppressed")
+	}, time.Second, 5*time.Millisecond, "the suppressed entries are reported when the traffic stops")
+
+	expected := `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "2 similar entries suppressed",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.code": "OK",
+    "grpc.suppressed": 2
+}`
+
+	assertLogMessage(t, expected, filterLogMessages(buf.String(), "similar entries suppressed"))
+}
@@ -6,12 +6,12 @@ import (
 	"time"
 
 	"github.com/bool64/ctxd"
-	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 )
 
 func newServerLogger(log ctxd.Logger, opts ...Option) *logger {
 	l := defaultLogger(log)
+	l.kind = "server"
 	l.codeToLevel = DefaultCodeToLevel
 
 	for _, o := range opts {
@@ -29,18 +29,39 @@ func UnaryServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.UnaryServer
 		startTime := time.Now()
 
 		ctx = serverLoggerContext(ctx, info.FullMethod, startTime)
+		ctx = l.debugContext(ctx, info.FullMethod)
+		ctx = l.incomingMetadataContext(ctx)
+		ctx = l.peerContext(ctx)
+
+		stopWatching := func() {}
+
+		if l.shouldLog(info.FullMethod, nil) {
+			l.logStartCall(ctx, "started unary call")
+
+			stopWatching = l.watchSlowCall(ctx, info.FullMethod, "unary call is still running", startTime)
+		}
+
+		logPayload := l.shouldLogPayloadOf(ctx, info.FullMethod)
+
+		if logPayload {
+			l.logPayload(ctx, "server request payload logged", FieldRequestContent, req)
+		}
+
 		resp, err := handler(ctx, req)
 
+		stopWatching()
+
+		if logPayload && err == nil {
+			l.logPayload(ctx, "server response payload logged", FieldResponseContent, resp)
+		}
+
 		duration := time.Since(startTime)
 
 		if !l.shouldLog(info.FullMethod, err) {
 			return resp, err
 		}
 
-		code := l.errorToCode(err)
-		level := l.codeToLevel(code)
-
-		l.Write(ctx, level, "finished unary call", code, err, duration)
+		l.writeCall(l.stackContext(ctx, err), info.FullMethod, "finished unary call", err, duration)
 
 		return resp, err
 	}
@@ -54,21 +75,38 @@ func StreamServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.StreamServ
 		startTime := time.Now()
 
 		ctx := serverLoggerContext(stream.Context(), info.FullMethod, startTime)
-		wrapped := grpcMiddleware.WrapServerStream(stream)
-		wrapped.WrappedContext = ctx //nolint: fatcontext
+		ctx = l.debugContext(ctx, info.FullMethod)
+		ctx = l.incomingMetadataContext(ctx)
+		ctx = l.peerContext(ctx)
+
+		stopWatching := func() {}
+
+		if l.shouldLog(info.FullMethod, nil) {
+			l.logStartCall(ctx, "started streaming call")
+
+			stopWatching = l.watchSlowCall(ctx, info.FullMethod, "streaming call is still running", startTime)
+		}
+
+		wrapped := &loggingServerStream{
+			ServerStream: stream,
+			ctx:          ctx,
+			logger:       l,
+			logPayload:   l.shouldLogPayloadOf(ctx, info.FullMethod),
+		}
 
 		err := handler(srv, wrapped)
 
+		stopWatching()
+
 		duration := time.Since(startTime)
 
 		if !l.shouldLog(info.FullMethod, err) {
 			return err
 		}
 
-		code := l.errorToCode(err)
-		level := l.codeToLevel(code)
+		ctx = contextWithStreamStats(l.stackContext(ctx, err), wrapped.stats())
 
-		l.Write(ctx, level, "finished streaming call", code, err, duration)
+		l.writeCall(ctx, info.FullMethod, "finished streaming call", err, duration)
 
 		return err
 	}
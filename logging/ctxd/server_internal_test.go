@@ -203,6 +203,10 @@ func TestStreamServerInterceptor(t *testing.T) {
     "span.kind": "server",
     "grpc.service": "grpctest.ItemService",
     "grpc.method": "ListItems",
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
     "grpc.start_time": "<ignore-diff>",
     "grpc.code": "Internal",
     "grpc.duration_ms": "<ignore-diff>",
@@ -224,6 +228,10 @@ func TestStreamServerInterceptor(t *testing.T) {
     "span.kind": "server",
     "grpc.service": "grpctest.ItemService",
     "grpc.method": "ListItems",
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
     "grpc.start_time": "<ignore-diff>",
     "grpc.code": "OK",
     "grpc.duration_ms": "<ignore-diff>"
@@ -244,6 +252,10 @@ func TestStreamServerInterceptor(t *testing.T) {
     "span.kind": "server",
     "grpc.service": "grpctest.ItemService",
     "grpc.method": "ListItems",
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
     "grpc.request.deadline": "<ignore-diff>",
     "grpc.start_time": "<ignore-diff>",
     "grpc.code": "OK",
@@ -0,0 +1,106 @@
+package ctxd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bool64/ctxd"
+)
+
+// FieldSlow is a context field for the calls that are slower than the slow call threshold.
+const FieldSlow = "grpc.slow"
+
+// WithSlowCallThreshold sets the duration above which a call is slow. The slow calls are logged at least at warn level,
+// with the grpc.slow field. A zero duration disables the detection.
+func WithSlowCallThreshold(threshold time.Duration) Option {
+	return func(l *logger) {
+		l.slowThreshold = threshold
+	}
+}
+
+// WithMethodSlowCallThreshold sets the slow call threshold of a full method name, e.g. "/pkg.Service/Method". It
+// overrides the threshold of WithSlowCallThreshold, a zero duration disables the detection for the method.
+func WithMethodSlowCallThreshold(fullMethod string, threshold time.Duration) Option {
+	return func(l *logger) {
+		if l.methodSlowThresholds == nil {
+			l.methodSlowThresholds = make(map[string]time.Duration)
+		}
+
+		l.methodSlowThresholds[fullMethod] = threshold
+	}
+}
+
+// WithSlowCallProgress logs, at warn level, the calls that are still running once they exceed the slow call
+// threshold, and then at every interval until they end. A zero interval logs only once.
+func WithSlowCallProgress(interval time.Duration) Option {
+	return func(l *logger) {
+		l.slowProgress = true
+		l.slowProgressInterval = interval
+	}
+}
+
+func (l *logger) slowCallThreshold(fullMethod string) time.Duration {
+	if threshold, ok := l.methodSlowThresholds[fullMethod]; ok {
+		return threshold
+	}
+
+	return l.slowThreshold
+}
+
+// watchSlowCall logs the call while it is still running after the slow call threshold, if enabled. The watch stops when
+// the returned function is called, or when the context is done, so an abandoned stream whose context is canceled is not
+// logged forever.
+func (l *logger) watchSlowCall(ctx context.Context, fullMethod string, msg string, start time.Time) func() {
+	threshold := l.slowCallThreshold(fullMethod)
+
+	if !l.slowProgress || threshold <= 0 {
+		return func() {}
+	}
+
+	var (
+		mu      sync.Mutex
+		stopped bool
+		timer   *time.Timer
+	)
+
+	// The callback waits for the timer to be assigned.
+	mu.Lock()
+	defer mu.Unlock()
+
+	timer = time.AfterFunc(threshold, func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if stopped || ctx.Err() != nil {
+			return
+		}
+
+		ctx := ctxd.AddFields(ctx,
+			FieldSlow, true,
+			FieldDuration, DurationInMilliseconds(time.Since(start)),
+		)
+
+		l.write(ctx, LogLevelWarn, msg)
+
+		if l.slowProgressInterval > 0 {
+			timer.Reset(l.slowProgressInterval)
+		}
+	})
+
+	stop := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		stopped = true
+
+		timer.Stop()
+	}
+
+	stopOnDone := context.AfterFunc(ctx, stop)
+
+	return func() {
+		stopOnDone()
+		stop()
+	}
+}
@@ -0,0 +1,328 @@
+package ctxd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bool64/zapctxd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor_SlowCall(t *testing.T) {
+	t.Parallel()
+
+	const fullMethod = "/grpctest.ItemService/GetItem"
+
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+
+	testCases := []struct {
+		scenario           string
+		options            []Option
+		handlerError       error
+		expectedLogMessage string
+	}{
+		{
+			scenario: "fast",
+			options:  []Option{WithSlowCallThreshold(time.Hour)},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario: "slow",
+			options:  []Option{WithSlowCallThreshold(10 * time.Millisecond)},
+			expectedLogMessage: `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario:     "slow error keeps its level",
+			options:      []Option{WithSlowCallThreshold(10 * time.Millisecond)},
+			handlerError: status.Error(codes.Internal, "internal error"),
+			expectedLogMessage: `{
+    "level": "error",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.code": "Internal",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = Internal desc = internal error"
+}`,
+		},
+		{
+			scenario: "method threshold",
+			options: []Option{
+				WithSlowCallThreshold(time.Hour),
+				WithMethodSlowCallThreshold(fullMethod, 10*time.Millisecond),
+			},
+			expectedLogMessage: `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+		{
+			scenario: "disabled for the method",
+			options: []Option{
+				WithSlowCallThreshold(10 * time.Millisecond),
+				WithMethodSlowCallThreshold(fullMethod, 0),
+			},
+			expectedLogMessage: `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+
+			_, _ = UnaryServerInterceptor(logger, tc.options...)(context.Background(), nil, info, func(context.Context, any) (any, error) { //nolint: errcheck
+				time.Sleep(20 * time.Millisecond)
+
+				return nil, tc.handlerError
+			})
+
+			assertLogMessage(t, tc.expectedLogMessage, buf.String())
+		})
+	}
+}
+
+func TestStreamServerInterceptor_SlowCallProgress(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+
+	interceptor := StreamServerInterceptor(logger,
+		WithSlowCallThreshold(20*time.Millisecond),
+		WithSlowCallProgress(20*time.Millisecond),
+	)
+
+	err := interceptor(nil, serverStreamWithContext(context.Background()), info, func(any, grpc.ServerStream) error {
+		time.Sleep(70 * time.Millisecond)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	progress := filterLogMessages(buf.String(), "streaming call is still running")
+
+	assert.GreaterOrEqual(t, countLogMessages(progress), 2)
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, progress, filterLogMessages(buf.String(), "streaming call is still running"), "the progress must stop when the call ends")
+
+	expected := `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "streaming call is still running",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, progress[:strings.Index(progress, "\n")+1])
+}
+
+func TestUnaryClientInterceptor_SlowCallProgress(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	interceptor := UnaryClientInterceptor(logger,
+		WithSlowCallThreshold(10*time.Millisecond),
+		WithSlowCallProgress(0),
+	)
+
+	err := interceptor(context.Background(), "/grpctest.ItemService/GetItem", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			time.Sleep(50 * time.Millisecond)
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	expected := []string{
+		`{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "client unary call is still running",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		`{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+	}
+
+	assertLogMessages(t, expected, buf.String())
+}
+
+func TestStreamClientInterceptor_SlowCall(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &clientStream{}, nil
+	}
+
+	stream, err := StreamClientInterceptor(logger, WithSlowCallThreshold(10*time.Millisecond))(context.Background(), nil, nil, "/grpctest.ItemService/ListItems", streamer)
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	require.Error(t, stream.RecvMsg(nil))
+
+	expected := `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.slow": true,
+    "grpc.msg.sent": 0,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 0,
+    "grpc.bytes.received": 0,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamClientInterceptor_SlowCallProgress_Canceled(t *testing.T) {
+	t.Parallel()
+
+	// The progress is logged concurrently with the test.
+	buf := new(syncBuffer)
+	logger := zapctxd.New(zapctxd.Config{Level: levelToZapLevel(LogLevelInfo), Output: buf})
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &clientStream{}, nil
+	}
+
+	interceptor := StreamClientInterceptor(logger,
+		WithSlowCallThreshold(10*time.Millisecond),
+		WithSlowCallProgress(10*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The stream is never read.
+	_, err := interceptor(ctx, nil, nil, "/grpctest.ItemService/ListItems", streamer)
+	require.NoError(t, err)
+
+	time.Sleep(35 * time.Millisecond)
+
+	cancel()
+
+	// Wait for a running callback, if any, to finish.
+	time.Sleep(5 * time.Millisecond)
+
+	progress := filterLogMessages(buf.String(), "client streaming call is still running")
+
+	assert.GreaterOrEqual(t, countLogMessages(progress), 1)
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, progress, filterLogMessages(buf.String(), "client streaming call is still running"), "the progress must stop when the context is canceled")
+}
+
+// syncBuffer is a bytes.Buffer that can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
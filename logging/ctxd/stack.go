@@ -0,0 +1,158 @@
+package ctxd
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/codes"
+)
+
+// FieldErrorStack is a context field for the stack trace of an error.
+const FieldErrorStack = "grpc.error.stack"
+
+const maxStackDepth = 64
+
+type errorFormatterCtxKey struct{}
+
+// StackTracer is an error that carries the stack trace of its origin, as program counters, e.g. the errors of
+// github.com/go-errors/errors. The errors with a StackTrace method returning the frames as program counters, e.g. the
+// errors of github.com/pkg/errors, are supported too.
+type StackTracer interface {
+	Callers() []uintptr
+}
+
+// ErrorFormatter renders an error into context fields, as key-value pairs.
+type ErrorFormatter func(err error) []any
+
+// WithStackTrace adds the stack trace of the errors to the logs of the server interceptors, for the given codes, or
+// codes.Internal and codes.Unknown if none. The stack trace of the origin is used if the error carries one, see
+// StackTracer, otherwise the stack trace is captured at the interceptor.
+func WithStackTrace(codeList ...codes.Code) Option {
+	if len(codeList) == 0 {
+		codeList = []codes.Code{codes.Internal, codes.Unknown}
+	}
+
+	return func(l *logger) {
+		l.stackTraceCodes = make(map[codes.Code]struct{}, len(codeList))
+
+		for _, c := range codeList {
+			l.stackTraceCodes[c] = struct{}{}
+		}
+	}
+}
+
+// WithErrorFormatter customizes how the errors are rendered into context fields by DefaultMessageProducer, e.g. to log
+// every error of a wrapped error chain. By default, the error is logged in the "error" field. A custom MessageProducer
+// has to apply the formatter itself, see ErrorFormatterFromContext.
+func WithErrorFormatter(f ErrorFormatter) Option {
+	return func(l *logger) {
+		l.errorFormatter = f
+	}
+}
+
+// stackContext adds the stack trace of the error to the context fields, if enabled for the code of the error.
+func (l *logger) stackContext(ctx context.Context, err error) context.Context {
+	if err == nil || len(l.stackTraceCodes) == 0 {
+		return ctx
+	}
+
+	if _, ok := l.stackTraceCodes[l.errorToCode(err)]; !ok {
+		return ctx
+	}
+
+	pcs, ok := originStack(err)
+	if !ok {
+		pcs = make([]uintptr, maxStackDepth)
+		// Skip runtime.Callers and stackContext.
+		pcs = pcs[:runtime.Callers(2, pcs)]
+	}
+
+	return ctxd.AddFields(ctx, FieldErrorStack, formatStack(pcs))
+}
+
+// originStack returns the stack trace of the first error of the chain that carries one, see StackTracer.
+func originStack(err error) ([]uintptr, bool) {
+	if st, ok := err.(StackTracer); ok { //nolint: errorlint // The chain is walked below.
+		return st.Callers(), true
+	}
+
+	if pcs, ok := stackTraceOf(err); ok {
+		return pcs, true
+	}
+
+	switch e := err.(type) { //nolint: errorlint // The chain is walked here.
+	case interface{ Unwrap() error }:
+		if next := e.Unwrap(); next != nil {
+			return originStack(next)
+		}
+
+	case interface{ Unwrap() []error }:
+		for _, next := range e.Unwrap() {
+			if pcs, ok := originStack(next); ok {
+				return pcs, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// stackTraceOf returns the frames of a StackTrace method, like the one of github.com/pkg/errors, without depending on
+// the package: the frames are a slice of program counters.
+func stackTraceOf(err error) ([]uintptr, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil, false
+	}
+
+	t := method.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+
+	return pcs, true
+}
+
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+
+		if frame.Function != "" {
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteString(":")
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteString("\n")
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// ErrorFormatterFromContext returns the formatter of WithErrorFormatter, if any. The context must be the one given to
+// the MessageProducer, so a custom MessageProducer can render the errors like DefaultMessageProducer does.
+func ErrorFormatterFromContext(ctx context.Context) (ErrorFormatter, bool) {
+	f, ok := ctx.Value(errorFormatterCtxKey{}).(ErrorFormatter)
+
+	return f, ok
+}
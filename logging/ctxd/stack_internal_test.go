@@ -0,0 +1,263 @@
+package ctxd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type stackError struct {
+	error
+
+	callers []uintptr
+}
+
+func (e *stackError) Callers() []uintptr {
+	return e.callers
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+
+	return &stackError{
+		error:   status.Error(codes.Internal, msg),
+		callers: pcs[:runtime.Callers(1, pcs)],
+	}
+}
+
+// pkgFrame and pkgStackTrace mimic the stack trace of github.com/pkg/errors.
+type (
+	pkgFrame      uintptr
+	pkgStackTrace []pkgFrame
+)
+
+type pkgStackError struct {
+	error
+
+	stack pkgStackTrace
+}
+
+func (e *pkgStackError) StackTrace() pkgStackTrace {
+	return e.stack
+}
+
+func newPkgStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(1, pcs)]
+
+	stack := make(pkgStackTrace, len(pcs))
+
+	for i, pc := range pcs {
+		stack[i] = pkgFrame(pc)
+	}
+
+	return &pkgStackError{
+		error: status.Error(codes.Internal, msg),
+		stack: stack,
+	}
+}
+
+func TestUnaryServerInterceptor_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		options       []Option
+		error         error
+		expectedStack string
+	}{
+		{
+			scenario: "disabled",
+			error:    status.Error(codes.Internal, "internal error"),
+		},
+		{
+			scenario:      "internal",
+			options:       []Option{WithStackTrace()},
+			error:         status.Error(codes.Internal, "internal error"),
+			expectedStack: "logging/ctxd.UnaryServerInterceptor.func1",
+		},
+		{
+			scenario:      "unknown",
+			options:       []Option{WithStackTrace()},
+			error:         errors.New("unknown error"),
+			expectedStack: "logging/ctxd.UnaryServerInterceptor.func1",
+		},
+		{
+			scenario: "other code",
+			options:  []Option{WithStackTrace()},
+			error:    status.Error(codes.NotFound, "not found"),
+		},
+		{
+			scenario:      "custom codes",
+			options:       []Option{WithStackTrace(codes.NotFound)},
+			error:         status.Error(codes.NotFound, "not found"),
+			expectedStack: "logging/ctxd.UnaryServerInterceptor.func1",
+		},
+		{
+			scenario:      "stack of the origin",
+			options:       []Option{WithStackTrace()},
+			error:         fmt.Errorf("get item: %w", newStackError("internal error")),
+			expectedStack: "logging/ctxd.newStackError",
+		},
+		{
+			scenario:      "stack of a pkg/errors origin",
+			options:       []Option{WithStackTrace()},
+			error:         fmt.Errorf("get item: %w", newPkgStackError("internal error")),
+			expectedStack: "logging/ctxd.newPkgStackError",
+		},
+		{
+			scenario:      "stack of a joined origin",
+			options:       []Option{WithStackTrace()},
+			error:         errors.Join(errors.New("other error"), newStackError("internal error")),
+			expectedStack: "logging/ctxd.newStackError",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			logger, buf := newCtxdLogger(LogLevelInfo)
+			info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+			_, err := UnaryServerInterceptor(logger, tc.options...)(context.Background(), nil, info, func(context.Context, any) (any, error) {
+				return nil, tc.error
+			})
+			require.Error(t, err)
+
+			var entry map[string]any
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+			stack, ok := entry[FieldErrorStack].(string)
+
+			if tc.expectedStack == "" {
+				assert.False(t, ok, "unexpected stack trace: %s", stack)
+
+				return
+			}
+
+			require.True(t, ok, "missing stack trace")
+
+			firstFunc, _, _ := strings.Cut(stack, "\n")
+
+			assert.Contains(t, firstFunc, tc.expectedStack)
+			assert.Contains(t, stack, "stack_internal_test.go")
+		})
+	}
+}
+
+func TestStreamServerInterceptor_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+
+	err := StreamServerInterceptor(logger, WithStackTrace())(nil, serverStreamWithContext(context.Background()), info, func(any, grpc.ServerStream) error {
+		return status.Error(codes.Internal, "internal error")
+	})
+	require.Error(t, err)
+
+	assert.Contains(t, buf.String(), `"grpc.error.stack":"github.com/nhatthm/go-grpc-middleware/logging/ctxd.StreamServerInterceptor.func1`)
+}
+
+func TestWithErrorFormatter(t *testing.T) {
+	t.Parallel()
+
+	formatter := func(err error) []any {
+		var chain []string
+
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			chain = append(chain, e.Error())
+		}
+
+		return []any{"error", err.Error(), "error.chain", chain}
+	}
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	err := UnaryClientInterceptor(logger, WithErrorFormatter(formatter))(context.Background(), "/grpctest.ItemService/GetItem", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return fmt.Errorf("get item: %w", status.Error(codes.NotFound, "not found"))
+		},
+	)
+	require.Error(t, err)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "NotFound",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "get item: rpc error: code = NotFound desc = not found",
+    "error.chain": [
+        "get item: rpc error: code = NotFound desc = not found",
+        "rpc error: code = NotFound desc = not found"
+    ]
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestErrorFormatterFromContext_CustomMessageProducer(t *testing.T) {
+	t.Parallel()
+
+	formatter := func(err error) []any {
+		return []any{"error.message", err.Error()}
+	}
+
+	producer := func(ctx context.Context, msg string, _ codes.Code, err error, _ time.Duration) (context.Context, string) {
+		if format, ok := ErrorFormatterFromContext(ctx); ok && err != nil {
+			ctx = ctxd.AddFields(ctx, format(err)...)
+		}
+
+		return ctx, msg
+	}
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	_ = UnaryClientInterceptor(logger, WithErrorFormatter(formatter), WithMessageProducer(producer))(context.Background(), "/grpctest.ItemService/GetItem", nil, nil, nil, //nolint: errcheck
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return status.Error(codes.NotFound, "not found")
+		},
+	)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "error.message": "rpc error: code = NotFound desc = not found"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestErrorFormatterFromContext_NoFormatter(t *testing.T) {
+	t.Parallel()
+
+	_, ok := ErrorFormatterFromContext(context.Background())
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,175 @@
+package ctxd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestUnaryServerInterceptor_StartCallLogging(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		logger, buf := newCtxdLogger(LogLevelInfo)
+
+		_, err := UnaryServerInterceptor(logger, WithStartCallLogging(LogLevelInfo))(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			return 42, nil
+		})
+		require.NoError(t, err)
+
+		expected := []string{
+			`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "started unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>"
+}`,
+			`{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished unary call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`,
+		}
+
+		assertLogMessages(t, expected, buf.String())
+	})
+
+	t.Run("below the level of the logger", func(t *testing.T) {
+		t.Parallel()
+
+		logger, buf := newCtxdLogger(LogLevelInfo)
+
+		_, err := UnaryServerInterceptor(logger, WithStartCallLogging(LogLevelDebug))(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			return 42, nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, countLogMessages(buf.String()))
+	})
+
+	t.Run("not accepted by the decider", func(t *testing.T) {
+		t.Parallel()
+
+		logger, buf := newCtxdLogger(LogLevelDebug)
+
+		_, err := UnaryServerInterceptor(logger,
+			WithStartCallLogging(LogLevelInfo),
+			WithDecider(func(string, error) bool {
+				return false
+			}),
+		)(context.Background(), nil, info, func(context.Context, any) (any, error) {
+			return 42, nil
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, buf.String())
+	})
+}
+
+func TestStreamServerInterceptor_StartCallLogging(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+
+	err := StreamServerInterceptor(logger, WithStartCallLogging(LogLevelWarn))(nil, serverStreamWithContext(context.Background()), info, func(any, grpc.ServerStream) error {
+		assert.Equal(t, 1, countLogMessages(buf.String()), "the start of the call must be logged before the handler")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	expected := `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "started streaming call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, filterLogMessages(buf.String(), "started"))
+}
+
+func TestUnaryClientInterceptor_StartCallLogging(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	err := UnaryClientInterceptor(logger, WithStartCallLogging(LogLevelDebug))(context.Background(), "/grpctest.ItemService/GetItem", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "started client unary call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "GetItem",
+    "grpc.start_time": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, filterLogMessages(buf.String(), "started"))
+	assert.Equal(t, 2, countLogMessages(buf.String()))
+}
+
+func TestStreamClientInterceptor_StartCallLogging(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &clientStream{}, nil
+	}
+
+	stream, err := StreamClientInterceptor(logger, WithStartCallLogging(LogLevelInfo))(context.Background(), nil, nil, "/grpctest.ItemService/ListItems", streamer)
+	require.NoError(t, err)
+
+	expected := `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "started client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+
+	require.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+
+	assert.Equal(t, 2, countLogMessages(buf.String()))
+}
+
+func countLogMessages(logs string) int {
+	return strings.Count(logs, "\n")
+}
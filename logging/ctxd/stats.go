@@ -0,0 +1,69 @@
+package ctxd
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type streamStatsCtxKey struct{}
+
+// StreamStats are the statistics of a stream.
+type StreamStats struct {
+	// MessagesSent is the number of messages sent.
+	MessagesSent int64
+	// MessagesReceived is the number of messages received.
+	MessagesReceived int64
+	// BytesSent is the serialized size of the messages sent, only proto.Message are measured.
+	BytesSent int64
+	// BytesReceived is the serialized size of the messages received, only proto.Message are measured.
+	BytesReceived int64
+}
+
+// StreamStatsFromContext returns the statistics of the stream, if the call is a stream. The context must be the one
+// given to the MessageProducer.
+func StreamStatsFromContext(ctx context.Context) (StreamStats, bool) {
+	stats, ok := ctx.Value(streamStatsCtxKey{}).(StreamStats)
+
+	return stats, ok
+}
+
+func contextWithStreamStats(ctx context.Context, stats StreamStats) context.Context {
+	return context.WithValue(ctx, streamStatsCtxKey{}, stats)
+}
+
+// streamCounter counts the messages of a stream and their sizes.
+type streamCounter struct {
+	messagesSent     atomic.Int64
+	messagesReceived atomic.Int64
+	bytesSent        atomic.Int64
+	bytesReceived    atomic.Int64
+}
+
+func (c *streamCounter) sent(m any) {
+	c.messagesSent.Add(1)
+	c.bytesSent.Add(messageSize(m))
+}
+
+func (c *streamCounter) received(m any) {
+	c.messagesReceived.Add(1)
+	c.bytesReceived.Add(messageSize(m))
+}
+
+func (c *streamCounter) stats() StreamStats {
+	return StreamStats{
+		MessagesSent:     c.messagesSent.Load(),
+		MessagesReceived: c.messagesReceived.Load(),
+		BytesSent:        c.bytesSent.Load(),
+		BytesReceived:    c.bytesReceived.Load(),
+	}
+}
+
+func messageSize(m any) int64 {
+	if m, ok := m.(proto.Message); ok {
+		return int64(proto.Size(m))
+	}
+
+	return 0
+}
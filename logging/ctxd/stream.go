@@ -0,0 +1,123 @@
+package ctxd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// loggingServerStream replaces the context of the stream, counts and logs the messages.
+type loggingServerStream struct {
+	grpc.ServerStream
+	streamCounter
+
+	ctx        context.Context //nolint: containedctx
+	logger     *logger
+	logPayload bool
+}
+
+func (s *loggingServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggingServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err != nil {
+		return err
+	}
+
+	s.sent(m)
+
+	if s.logPayload {
+		s.logger.logPayload(s.ctx, "server response payload logged", FieldResponseContent, m)
+	}
+
+	return nil
+}
+
+func (s *loggingServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+
+	s.received(m)
+
+	if s.logPayload {
+		s.logger.logPayload(s.ctx, "server request payload logged", FieldRequestContent, m)
+	}
+
+	return nil
+}
+
+// loggingClientStream counts and logs the messages of the stream, and logs the call when the stream ends.
+type loggingClientStream struct {
+	grpc.ClientStream
+	streamCounter
+
+	ctx           context.Context //nolint: containedctx
+	peer          *peer.Peer
+	logger        *logger
+	logPayload    bool
+	method        string
+	startTime     time.Time
+	serverStreams bool
+	stopWatching  func()
+
+	finishOnce sync.Once
+}
+
+func (s *loggingClientStream) SendMsg(m any) error {
+	err := s.ClientStream.SendMsg(m)
+	if err != nil {
+		return err
+	}
+
+	s.sent(m)
+
+	if s.logPayload {
+		s.logger.logPayload(s.ctx, "client request payload logged", FieldRequestContent, m)
+	}
+
+	return nil
+}
+
+func (s *loggingClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(err)
+
+		return err
+	}
+
+	s.received(m)
+
+	if s.logPayload {
+		s.logger.logPayload(s.ctx, "client response payload logged", FieldResponseContent, m)
+	}
+
+	if !s.serverStreams {
+		s.finish(nil)
+	}
+
+	return nil
+}
+
+func (s *loggingClientStream) finish(err error) {
+	s.finishOnce.Do(func() {
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+
+		s.stopWatching()
+
+		ctx := contextWithStreamStats(s.logger.addPeerFields(s.ctx, s.peer), s.stats())
+
+		s.logger.writeCall(ctx, s.method, "finished client streaming call", err, time.Since(s.startTime))
+	})
+}
@@ -0,0 +1,225 @@
+package ctxd
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestStreamClientInterceptor_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	conn := newEchoConn(t, grpc.WithChainStreamInterceptor(StreamClientInterceptor(logger,
+		WithPayloadLogging(func(context.Context, string) bool {
+			return true
+		}),
+	)))
+
+	stream, err := conn.NewStream(context.Background(), echoStreamDesc, echoMethod)
+	require.NoError(t, err)
+
+	for _, msg := range []string{"hello", "world"} {
+		require.NoError(t, stream.SendMsg(wrapperspb.String(msg)))
+		require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+	}
+
+	assert.Empty(t, filterLogMessages(buf.String(), "finished client streaming call"), "the stream is not finished")
+
+	require.NoError(t, stream.CloseSend())
+	require.ErrorIs(t, stream.RecvMsg(new(wrapperspb.StringValue)), io.EOF)
+	require.ErrorIs(t, stream.RecvMsg(new(wrapperspb.StringValue)), io.EOF)
+
+	expected := []string{`{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.EchoService",
+    "grpc.method": "Echo",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 2,
+    "grpc.msg.received": 2,
+    "grpc.bytes.sent": 14,
+    "grpc.bytes.received": 14,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`}
+
+	assertLogMessages(t, expected, filterLogMessages(buf.String(), "finished client streaming call"))
+	assert.Equal(t, 4, strings.Count(filterLogMessages(buf.String(), "payload logged"), "\n"))
+}
+
+func TestStreamClientInterceptor_Lifecycle_Error(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &erroredClientStream{err: status.Error(codes.Unavailable, "unavailable")}, nil
+	}
+
+	stream, err := StreamClientInterceptor(logger)(context.Background(), echoStreamDesc, nil, echoMethod, streamer)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.SendMsg(wrapperspb.String("hello")))
+	require.EqualError(t, stream.RecvMsg(nil), "rpc error: code = Unavailable desc = unavailable")
+
+	expected := `{
+    "level": "warn",
+    "time": "<ignore-diff>",
+    "msg": "finished client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.EchoService",
+    "grpc.method": "Echo",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 1,
+    "grpc.msg.received": 0,
+    "grpc.bytes.sent": 7,
+    "grpc.bytes.received": 0,
+    "grpc.code": "Unavailable",
+    "grpc.duration_ms": "<ignore-diff>",
+    "error": "rpc error: code = Unavailable desc = unavailable"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamClientInterceptor_Lifecycle_ClientStreaming(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelDebug)
+
+	conn := newEchoConn(t, grpc.WithChainStreamInterceptor(StreamClientInterceptor(logger)))
+
+	desc := &grpc.StreamDesc{StreamName: "Echo", ClientStreams: true}
+
+	stream, err := conn.NewStream(context.Background(), desc, echoMethod)
+	require.NoError(t, err)
+
+	require.NoError(t, stream.SendMsg(wrapperspb.String("hello")))
+	require.NoError(t, stream.CloseSend())
+	require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+
+	expected := `{
+    "level": "debug",
+    "time": "<ignore-diff>",
+    "msg": "finished client streaming call",
+    "system": "grpc",
+    "span.kind": "client",
+    "grpc.service": "grpctest.EchoService",
+    "grpc.method": "Echo",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 1,
+    "grpc.msg.received": 1,
+    "grpc.bytes.sent": 7,
+    "grpc.bytes.received": 7,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamServerInterceptor_Stats(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newCtxdLogger(LogLevelInfo)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+	stream := &messageServerStream{serverStream: serverStream{context: context.Background()}}
+
+	var actual StreamStats
+
+	interceptor := StreamServerInterceptor(logger,
+		WithMessageProducer(func(ctx context.Context, msg string, code codes.Code, err error, duration time.Duration) (context.Context, string) {
+			actual, _ = StreamStatsFromContext(ctx)
+
+			return DefaultMessageProducer(ctx, msg, code, err, duration)
+		}),
+	)
+
+	err := interceptor(nil, stream, info, func(_ any, stream grpc.ServerStream) error {
+		require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+		require.NoError(t, stream.RecvMsg(new(wrapperspb.StringValue)))
+		require.NoError(t, stream.SendMsg(wrapperspb.String("response")))
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	expectedStats := StreamStats{
+		MessagesSent:     1,
+		MessagesReceived: 2,
+		BytesSent:        10,
+		BytesReceived:    18,
+	}
+
+	assert.Equal(t, expectedStats, actual)
+
+	expected := `{
+    "level": "info",
+    "time": "<ignore-diff>",
+    "msg": "finished streaming call",
+    "system": "grpc",
+    "span.kind": "server",
+    "grpc.service": "grpctest.ItemService",
+    "grpc.method": "ListItems",
+    "grpc.start_time": "<ignore-diff>",
+    "grpc.msg.sent": 1,
+    "grpc.msg.received": 2,
+    "grpc.bytes.sent": 10,
+    "grpc.bytes.received": 18,
+    "grpc.code": "OK",
+    "grpc.duration_ms": "<ignore-diff>"
+}`
+
+	assertLogMessage(t, expected, buf.String())
+}
+
+func TestStreamStatsFromContext_NotStream(t *testing.T) {
+	t.Parallel()
+
+	_, ok := StreamStatsFromContext(context.Background())
+
+	assert.False(t, ok)
+}
+
+type erroredClientStream struct {
+	grpc.ClientStream
+
+	err error
+}
+
+func (s *erroredClientStream) SendMsg(any) error {
+	return nil
+}
+
+func (s *erroredClientStream) RecvMsg(any) error {
+	return s.err
+}
+
+// filterLogMessages returns the log lines that contain the substring.
+func filterLogMessages(logs, substr string) string {
+	var sb strings.Builder
+
+	for _, line := range strings.Split(logs, "\n") {
+		if strings.Contains(line, substr) {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,2 @@
+// Package recovery provides middlewares for recovering the panics of gRPC server handlers.
+package recovery
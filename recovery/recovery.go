@@ -0,0 +1,74 @@
+package recovery
+
+import (
+	"context"
+	"path"
+	"runtime/debug"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcCtxd "github.com/nhatthm/go-grpc-middleware/logging/ctxd"
+)
+
+const (
+	// FieldPanic is a context field for the recovered panic value.
+	FieldPanic = "grpc.panic"
+	// FieldPanicStack is a context field for the stack trace of the panic.
+	FieldPanicStack = "grpc.panic.stack"
+)
+
+// Handler converts a recovered panic into the error of the call.
+type Handler func(ctx context.Context, p any) error
+
+// Option configures the recovery interceptors.
+type Option func(c *config)
+
+type config struct {
+	handler Handler
+}
+
+func newConfig(opts ...Option) config {
+	c := config{
+		handler: DefaultHandler,
+	}
+
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// WithHandler sets the function that converts a recovered panic into the error of the call. The default is
+// DefaultHandler.
+func WithHandler(h Handler) Option {
+	return func(c *config) {
+		c.handler = h
+	}
+}
+
+// DefaultHandler returns a codes.Internal error, without the panic value, so nothing leaks to the clients.
+func DefaultHandler(context.Context, any) error {
+	return status.Error(codes.Internal, "internal error")
+}
+
+// recoverFrom logs the panic and converts it into the error of the call.
+func (c config) recoverFrom(ctx context.Context, logger ctxd.Logger, fullMethod string, p any) error {
+	if logger != nil {
+		logCtx := ctxd.SetFields(ctx,
+			grpcCtxd.FieldSystem, "grpc",
+			grpcCtxd.FieldKind, "server",
+			grpcCtxd.FieldService, path.Dir(fullMethod)[1:],
+			grpcCtxd.FieldMethod, path.Base(fullMethod),
+		)
+
+		logger.Error(logCtx, "recovered from panic",
+			FieldPanic, p,
+			FieldPanicStack, string(debug.Stack()),
+		)
+	}
+
+	return c.handler(ctx, p)
+}
@@ -0,0 +1,29 @@
+package recovery
+
+import (
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc"
+)
+
+// StreamServerInterceptor recovers the panics of the handlers, logs them with the logger, if not nil, and returns the
+// error of the handler of WithHandler, codes.Internal by default.
+//
+// The interceptor should be the last one of the chain, so the other interceptors, e.g. the logging ones, see the error.
+func StreamServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts...)
+
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = c.recoverFrom(stream.Context(), logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+// WithStreamServerInterceptor appends StreamServerInterceptor to server option.
+func WithStreamServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(StreamServerInterceptor(logger, opts...))
+}
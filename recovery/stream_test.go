@@ -0,0 +1,61 @@
+package recovery_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/recovery"
+)
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger()
+	interceptor := recovery.StreamServerInterceptor(logger)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+
+	err := interceptor(nil, &serverStream{ctx: context.Background()}, info, func(any, grpc.ServerStream) error {
+		panic("boom")
+	})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	entry := decodeLog(t, buf)
+
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "recovered from panic", entry["msg"])
+	assert.Equal(t, "grpctest.ItemService", entry["grpc.service"])
+	assert.Equal(t, "ListItems", entry["grpc.method"])
+	assert.Equal(t, "boom", entry[recovery.FieldPanic])
+	assert.NotEmpty(t, entry[recovery.FieldPanicStack])
+}
+
+func TestStreamServerInterceptor_NoPanic(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger()
+	interceptor := recovery.StreamServerInterceptor(logger)
+	info := &grpc.StreamServerInfo{FullMethod: "/grpctest.ItemService/ListItems"}
+
+	err := interceptor(nil, &serverStream{ctx: context.Background()}, info, func(any, grpc.ServerStream) error {
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
+
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint: containedctx
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,31 @@
+package recovery
+
+import (
+	"context"
+
+	"github.com/bool64/ctxd"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor recovers the panics of the handlers, logs them with the logger, if not nil, and returns the
+// error of the handler of WithHandler, codes.Internal by default.
+//
+// The interceptor should be the last one of the chain, so the other interceptors, e.g. the logging ones, see the error.
+func UnaryServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts...)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, c.recoverFrom(ctx, logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// WithUnaryServerInterceptor appends UnaryServerInterceptor to server option.
+func WithUnaryServerInterceptor(logger ctxd.Logger, opts ...Option) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(UnaryServerInterceptor(logger, opts...))
+}
@@ -0,0 +1,121 @@
+package recovery_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/zapctxd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/recovery"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger()
+	interceptor := recovery.UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.EqualError(t, err, "rpc error: code = Internal desc = internal error")
+
+	entry := decodeLog(t, buf)
+
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "recovered from panic", entry["msg"])
+	assert.Equal(t, "grpc", entry["system"])
+	assert.Equal(t, "server", entry["span.kind"])
+	assert.Equal(t, "grpctest.ItemService", entry["grpc.service"])
+	assert.Equal(t, "GetItem", entry["grpc.method"])
+	assert.Equal(t, "boom", entry[recovery.FieldPanic])
+	assert.Contains(t, entry[recovery.FieldPanicStack], "recovery_test.TestUnaryServerInterceptor")
+}
+
+func TestUnaryServerInterceptor_NoPanic(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger()
+	interceptor := recovery.UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return 42, status.Error(codes.NotFound, "not found")
+	})
+
+	assert.Equal(t, 42, resp)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Empty(t, buf.String())
+}
+
+func TestUnaryServerInterceptor_Handler(t *testing.T) {
+	t.Parallel()
+
+	var recovered any
+
+	interceptor := recovery.UnaryServerInterceptor(nil,
+		recovery.WithHandler(func(_ context.Context, p any) error {
+			recovered = p
+
+			return status.Error(codes.Unavailable, "try again")
+		}),
+	)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		panic(errors.New("boom"))
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.EqualError(t, recovered.(error), "boom")
+}
+
+func TestUnaryServerInterceptor_ExistingFields(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger()
+	interceptor := recovery.UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpctest.ItemService/GetItem"}
+
+	ctx := ctxd.AddFields(context.Background(), "grpc.service", "grpctest.ItemService", "grpc.method", "GetItem", "request.id", "42")
+
+	_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	entry := decodeLog(t, buf)
+
+	assert.Equal(t, "42", entry["request.id"])
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte(`"grpc.service"`)), "the fields must not be duplicated")
+}
+
+func newLogger() (ctxd.Logger, *bytes.Buffer) {
+	buf := new(bytes.Buffer)
+
+	return zapctxd.New(zapctxd.Config{Output: buf}), buf
+}
+
+func decodeLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var entry map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	return entry
+}
@@ -0,0 +1,154 @@
+package timeout
+
+import (
+	"sync"
+	"time"
+)
+
+// Observer learns from the latency of the calls. When the provider of the client interceptors is also an Observer,
+// the unary client interceptor reports the latency of every call that is not canceled and does not exceed the deadline
+// of the caller. A call that exceeds the timeout of the interceptor is reported with its elapsed time, i.e. about the
+// timeout, so an adaptive timeout that is too tight grows.
+type Observer interface {
+	// Observe records the latency of a call.
+	Observe(fullMethod string, latency time.Duration)
+}
+
+// AdaptiveOption configures an AdaptiveProvider.
+type AdaptiveOption func(p *AdaptiveProvider)
+
+// AdaptiveProvider is a provider that sets the timeout of a method from its observed latency: a quantile of the latency
+// times a multiplier, bounded by a minimum and a maximum. The fallback timeout is used until enough calls are observed.
+//
+// The quantile is estimated per method with the P² algorithm, so the result only depends on the observed latencies.
+// Observe can be called directly to feed the provider, e.g. in tests.
+type AdaptiveProvider struct {
+	fallback   time.Duration
+	quantile   float64
+	multiplier float64
+	minTimeout time.Duration
+	maxTimeout time.Duration
+	warmUp     int
+
+	mu         sync.RWMutex
+	estimators map[string]*methodEstimator
+}
+
+type methodEstimator struct {
+	mu        sync.Mutex
+	estimator *quantileEstimator
+}
+
+var (
+	_ Provider = (*AdaptiveProvider)(nil)
+	_ Observer = (*AdaptiveProvider)(nil)
+)
+
+// NewAdaptiveProvider creates a new adaptive provider. By default, the timeout is p99 × 1.5 after 100 observed calls.
+func NewAdaptiveProvider(fallback time.Duration, opts ...AdaptiveOption) *AdaptiveProvider {
+	p := &AdaptiveProvider{
+		fallback:   fallback,
+		quantile:   0.99,
+		multiplier: 1.5,
+		warmUp:     100,
+		estimators: make(map[string]*methodEstimator),
+	}
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	return p
+}
+
+// WithAdaptiveQuantile sets the quantile of the latency, between 0 and 1. The default is 0.99.
+func WithAdaptiveQuantile(quantile float64) AdaptiveOption {
+	return func(p *AdaptiveProvider) {
+		p.quantile = quantile
+	}
+}
+
+// WithAdaptiveMultiplier sets the multiplier of the quantile. The default is 1.5.
+func WithAdaptiveMultiplier(multiplier float64) AdaptiveOption {
+	return func(p *AdaptiveProvider) {
+		p.multiplier = multiplier
+	}
+}
+
+// WithAdaptiveBounds sets the minimum and the maximum timeout. A zero value means no bound.
+func WithAdaptiveBounds(minTimeout, maxTimeout time.Duration) AdaptiveOption {
+	return func(p *AdaptiveProvider) {
+		p.minTimeout = minTimeout
+		p.maxTimeout = maxTimeout
+	}
+}
+
+// WithAdaptiveWarmUp sets the number of calls to observe before adapting the timeout of a method. The default is 100.
+func WithAdaptiveWarmUp(calls int) AdaptiveOption {
+	return func(p *AdaptiveProvider) {
+		p.warmUp = calls
+	}
+}
+
+// Observe records the latency of a call.
+func (p *AdaptiveProvider) Observe(fullMethod string, latency time.Duration) {
+	e := p.estimator(fullMethod)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.estimator.Add(float64(latency))
+}
+
+// Timeout returns the adaptive timeout of the method, or the fallback timeout if there are not enough observed calls.
+func (p *AdaptiveProvider) Timeout(fullMethod string) time.Duration {
+	p.mu.RLock()
+	e, ok := p.estimators[fullMethod]
+	p.mu.RUnlock()
+
+	if !ok {
+		return p.fallback
+	}
+
+	e.mu.Lock()
+	count, value := e.estimator.Count(), e.estimator.Value()
+	e.mu.Unlock()
+
+	if count == 0 || count < p.warmUp {
+		return p.fallback
+	}
+
+	timeout := time.Duration(value * p.multiplier)
+
+	if p.minTimeout > 0 && timeout < p.minTimeout {
+		timeout = p.minTimeout
+	}
+
+	if p.maxTimeout > 0 && timeout > p.maxTimeout {
+		timeout = p.maxTimeout
+	}
+
+	return timeout
+}
+
+func (p *AdaptiveProvider) estimator(fullMethod string) *methodEstimator {
+	p.mu.RLock()
+	e, ok := p.estimators[fullMethod]
+	p.mu.RUnlock()
+
+	if ok {
+		return e
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.estimators[fullMethod]; ok {
+		return e
+	}
+
+	e = &methodEstimator{estimator: newQuantileEstimator(p.quantile)}
+	p.estimators[fullMethod] = e
+
+	return e
+}
@@ -0,0 +1,198 @@
+package timeout_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/timeout"
+)
+
+func TestAdaptiveProvider_Timeout(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	testCases := []struct {
+		scenario string
+		options  []timeout.AdaptiveOption
+		latency  time.Duration
+		expected time.Duration
+	}{
+		{
+			scenario: "default",
+			latency:  100 * time.Millisecond,
+			expected: 150 * time.Millisecond,
+		},
+		{
+			scenario: "multiplier",
+			options:  []timeout.AdaptiveOption{timeout.WithAdaptiveMultiplier(3)},
+			latency:  100 * time.Millisecond,
+			expected: 300 * time.Millisecond,
+		},
+		{
+			scenario: "min timeout",
+			options:  []timeout.AdaptiveOption{timeout.WithAdaptiveBounds(time.Second, time.Minute)},
+			latency:  100 * time.Millisecond,
+			expected: time.Second,
+		},
+		{
+			scenario: "max timeout",
+			options:  []timeout.AdaptiveOption{timeout.WithAdaptiveBounds(0, time.Second)},
+			latency:  time.Minute,
+			expected: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p := timeout.NewAdaptiveProvider(5*time.Second, tc.options...)
+
+			assert.Equal(t, 5*time.Second, p.Timeout(method))
+
+			for range 200 {
+				p.Observe(method, tc.latency)
+			}
+
+			assert.Equal(t, tc.expected, p.Timeout(method))
+			assert.Equal(t, 5*time.Second, p.Timeout("/pkg.Service/Other"))
+		})
+	}
+}
+
+func TestAdaptiveProvider_Quantile(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAdaptiveProvider(time.Minute,
+		timeout.WithAdaptiveQuantile(0.9),
+		timeout.WithAdaptiveMultiplier(1),
+		timeout.WithAdaptiveWarmUp(10),
+	)
+
+	for i := range 9 {
+		p.Observe(method, time.Duration(i+1)*time.Millisecond)
+	}
+
+	// Not enough calls.
+	assert.Equal(t, time.Minute, p.Timeout(method))
+
+	for i := 9; i < 1000; i++ {
+		p.Observe(method, time.Duration(i%100+1)*time.Millisecond)
+	}
+
+	assert.InDelta(t, 90*time.Millisecond, p.Timeout(method), float64(5*time.Millisecond))
+}
+
+func TestUnaryClientTimeoutProviderInterceptor_Observer(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAdaptiveProvider(time.Minute,
+		timeout.WithAdaptiveWarmUp(1),
+		timeout.WithAdaptiveBounds(time.Second, 0),
+	)
+
+	interceptor := timeout.UnaryClientTimeoutProviderInterceptor(p)
+
+	invoke := func(err error) {
+		_ = interceptor(context.Background(), method, nil, nil, nil, //nolint: errcheck
+			func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				return err
+			},
+		)
+	}
+
+	// Deadline exceeded and canceled calls are not observed.
+	invoke(status.Error(codes.DeadlineExceeded, "deadline exceeded"))
+	invoke(status.Error(codes.Canceled, "canceled"))
+
+	require.Equal(t, time.Minute, p.Timeout(method))
+
+	invoke(status.Error(codes.NotFound, "not found"))
+
+	assert.Equal(t, time.Second, p.Timeout(method))
+}
+
+func TestUnaryClientTimeoutProviderInterceptor_Observer_SlowPeriod(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAdaptiveProvider(time.Minute,
+		timeout.WithAdaptiveWarmUp(10),
+		timeout.WithAdaptiveQuantile(0.9),
+		timeout.WithAdaptiveMultiplier(2),
+	)
+
+	interceptor := timeout.UnaryClientTimeoutProviderInterceptor(p)
+
+	invoke := func(latency time.Duration) error {
+		return interceptor(context.Background(), method, nil, nil, nil,
+			func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				select {
+				case <-time.After(latency):
+					return nil
+
+				case <-ctx.Done():
+					return status.FromContextError(ctx.Err()).Err()
+				}
+			},
+		)
+	}
+
+	for range 10 {
+		require.NoError(t, invoke(time.Millisecond))
+	}
+
+	initial := p.Timeout(method)
+
+	require.Less(t, initial, 20*time.Millisecond)
+
+	// The latency goes up, the calls exceed the timeout until it grows.
+	var exceeded int
+
+	for range 30 {
+		if err := invoke(50 * time.Millisecond); status.Code(err) == codes.DeadlineExceeded {
+			exceeded++
+		}
+	}
+
+	assert.Positive(t, exceeded)
+	assert.Greater(t, p.Timeout(method), 50*time.Millisecond, "the timeout must grow during slow periods")
+	assert.NoError(t, invoke(50*time.Millisecond))
+}
+
+func TestUnaryClientTimeoutProviderInterceptor_Observer_CallerDeadline(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAdaptiveProvider(time.Minute, timeout.WithAdaptiveWarmUp(1))
+
+	interceptor := timeout.UnaryClientTimeoutProviderInterceptor(p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err := interceptor(ctx, method, nil, nil, nil,
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			<-ctx.Done()
+
+			return status.FromContextError(ctx.Err()).Err()
+		},
+	)
+
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, time.Minute, p.Timeout(method), "the deadline of the caller is not observed")
+}
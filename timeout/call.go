@@ -0,0 +1,49 @@
+package timeout
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// CallTimeoutOption is a grpc.CallOption that overrides the timeout of the client interceptors for a single call.
+// The option is removed before the call reaches the invoker.
+type CallTimeoutOption struct {
+	grpc.EmptyCallOption
+
+	Timeout time.Duration
+}
+
+// WithCallTimeout overrides the timeout of the client interceptors for a single call.
+func WithCallTimeout(timeout time.Duration) grpc.CallOption {
+	return CallTimeoutOption{Timeout: timeout}
+}
+
+// callTimeout finds the timeout of the call in the call options, the last one wins, then in the context. The
+// CallTimeoutOption are removed from the returned call options.
+func callTimeout(ctx context.Context, opts []grpc.CallOption) (time.Duration, []grpc.CallOption, bool) {
+	timeout, found := CallTimeoutFromContext(ctx)
+	removed := 0
+
+	for _, o := range opts {
+		if co, ok := o.(CallTimeoutOption); ok {
+			timeout, found = co.Timeout, true
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		return timeout, opts, found
+	}
+
+	filtered := make([]grpc.CallOption, 0, len(opts)-removed)
+
+	for _, o := range opts {
+		if _, ok := o.(CallTimeoutOption); !ok {
+			filtered = append(filtered, o)
+		}
+	}
+
+	return timeout, filtered, found
+}
@@ -3,9 +3,74 @@ package timeout
 import (
 	"context"
 	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	skipTimeoutCtxKey struct{}
+	callTimeoutCtxKey struct{}
 )
 
-type skipTimeoutCtxKey struct{}
+// Option configures the timeout interceptors.
+type Option func(c *config)
+
+type config struct {
+	maxTimeout time.Duration
+
+	reservedDuration time.Duration
+	reservedRatio    float64
+	minBudget        time.Duration
+}
+
+func newConfig(opts ...Option) config {
+	var c config
+
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// WithMaxTimeout sets the maximum timeout of a call. Any deadline that is further than the maximum timeout, including
+// the one set by the caller, is shortened to the maximum timeout. Shorter deadlines are respected.
+func WithMaxTimeout(maxTimeout time.Duration) Option {
+	return func(c *config) {
+		c.maxTimeout = maxTimeout
+	}
+}
+
+// WithReservedDuration reserves a duration from the deadline of the caller before sending the request, so there is
+// time left to build the response after the downstream call. It only applies to the client interceptors.
+func WithReservedDuration(d time.Duration) Option {
+	return func(c *config) {
+		c.reservedDuration = d
+	}
+}
+
+// WithReservedRatio reserves a ratio, in [0, 1), of the remaining time before the deadline of the caller before sending
+// the request. A ratio out of range is ignored. It only applies to the client interceptors and adds up to
+// WithReservedDuration.
+func WithReservedRatio(ratio float64) Option {
+	return func(c *config) {
+		if ratio < 0 || ratio >= 1 {
+			return
+		}
+
+		c.reservedRatio = ratio
+	}
+}
+
+// WithMinBudget sets the minimum remaining time, after the reservation, to send the request. If the remaining time is
+// below the minimum, the call fails with codes.DeadlineExceeded without being sent. It only applies to the client
+// interceptors.
+func WithMinBudget(d time.Duration) Option {
+	return func(c *config) {
+		c.minBudget = d
+	}
+}
 
 // IsTimeoutSkipped checks whether the timeout interceptor is bypassed.
 func IsTimeoutSkipped(ctx context.Context) bool {
@@ -19,15 +84,87 @@ func SkipTimeout(ctx context.Context) context.Context {
 	return context.WithValue(ctx, skipTimeoutCtxKey{}, true)
 }
 
-func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
-	_, deadlineIsSet := ctx.Deadline()
+// ContextWithCallTimeout overrides the timeout of the client interceptors for the calls made with the context.
+// WithCallTimeout takes precedence over the timeout in the context.
+func ContextWithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutCtxKey{}, timeout)
+}
+
+// CallTimeoutFromContext returns the timeout set by ContextWithCallTimeout.
+func CallTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, found := ctx.Value(callTimeoutCtxKey{}).(time.Duration)
+
+	return timeout, found
+}
+
+func (c config) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	deadline, deadlineIsSet := ctx.Deadline()
 	cancel := func() {
 		// Fake cancel function in case there is no timeout.
 	}
 
-	if timeout == 0 || deadlineIsSet || IsTimeoutSkipped(ctx) {
+	if IsTimeoutSkipped(ctx) {
+		return ctx, cancel
+	}
+
+	if c.maxTimeout > 0 {
+		if deadlineIsSet && time.Until(deadline) <= c.maxTimeout {
+			return ctx, cancel
+		}
+
+		if deadlineIsSet || timeout == 0 || timeout > c.maxTimeout {
+			return context.WithTimeout(ctx, c.maxTimeout)
+		}
+	}
+
+	if timeout == 0 || deadlineIsSet {
 		return ctx, cancel
 	}
 
 	return context.WithTimeout(ctx, timeout)
 }
+
+// withClientTimeout reserves the budget from the deadline of the caller before starting a context with timeout.
+func (c config) withClientTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, error) {
+	ctx, cancelBudget, err := c.withBudget(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancelTimeout := c.withTimeout(ctx, timeout)
+
+	return ctx, func() {
+		cancelTimeout()
+		cancelBudget()
+	}, nil
+}
+
+func (c config) withBudget(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	deadline, deadlineIsSet := ctx.Deadline()
+	cancel := func() {
+		// Fake cancel function in case there is no reservation.
+	}
+
+	if !deadlineIsSet || IsTimeoutSkipped(ctx) {
+		return ctx, cancel, nil
+	}
+
+	if c.reservedDuration == 0 && c.reservedRatio == 0 && c.minBudget == 0 {
+		return ctx, cancel, nil
+	}
+
+	remaining := time.Until(deadline)
+	budget := remaining - c.reservedDuration - time.Duration(float64(remaining)*c.reservedRatio)
+
+	if budget <= 0 || budget < c.minBudget {
+		return nil, nil, status.Errorf(codes.DeadlineExceeded, "deadline budget %s is below the minimum %s", budget, c.minBudget)
+	}
+
+	if budget == remaining {
+		return ctx, cancel, nil
+	}
+
+	ctx, cancel = context.WithDeadline(ctx, deadline.Add(budget-remaining))
+
+	return ctx, cancel, nil
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestIsTimeoutSkipped(t *testing.T) {
@@ -28,7 +31,7 @@ func TestWithTimeout_NoCancel(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	newCtx, cancelNewCtx := withTimeout(ctx, timeout)
+	newCtx, cancelNewCtx := newConfig().withTimeout(ctx, timeout)
 
 	cancelNewCtx()
 
@@ -46,7 +49,7 @@ func TestWithTimeout_NoTimeout(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
-	newCtx, cancel := withTimeout(ctx, 0)
+	newCtx, cancel := newConfig().withTimeout(ctx, 0)
 
 	cancel()
 
@@ -61,7 +64,7 @@ func TestWithTimeout_WithTimeout(t *testing.T) {
 	timeout := time.Millisecond * 50
 	ctx := context.Background()
 
-	newCtx, cancel := withTimeout(ctx, timeout)
+	newCtx, cancel := newConfig().withTimeout(ctx, timeout)
 	defer cancel()
 
 	time.Sleep(timeout * 2)
@@ -76,7 +79,7 @@ func TestWithTimeout_WithTimeout_ButSkipped(t *testing.T) {
 	timeout := time.Millisecond * 50
 	ctx := SkipTimeout(context.Background())
 
-	newCtx, cancel := withTimeout(ctx, timeout)
+	newCtx, cancel := newConfig().withTimeout(ctx, timeout)
 	defer cancel()
 
 	time.Sleep(timeout * 2)
@@ -84,3 +87,223 @@ func TestWithTimeout_WithTimeout_ButSkipped(t *testing.T) {
 	assert.NoError(t, ctx.Err())
 	assert.NoError(t, newCtx.Err())
 }
+
+func TestWithTimeout_MaxTimeout(t *testing.T) {
+	t.Parallel()
+
+	const maxTimeout = time.Minute
+
+	testCases := []struct {
+		scenario         string
+		context          func() (context.Context, context.CancelFunc)
+		timeout          time.Duration
+		expectedDeadline time.Duration
+	}{
+		{
+			scenario: "no deadline and no timeout",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			expectedDeadline: maxTimeout,
+		},
+		{
+			scenario: "no deadline and shorter timeout",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			timeout:          time.Second,
+			expectedDeadline: time.Second,
+		},
+		{
+			scenario: "no deadline and longer timeout",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			timeout:          time.Hour,
+			expectedDeadline: maxTimeout,
+		},
+		{
+			scenario: "shorter deadline is respected",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			timeout:          time.Hour,
+			expectedDeadline: time.Second,
+		},
+		{
+			scenario: "longer deadline is shortened",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 24*time.Hour)
+			},
+			expectedDeadline: maxTimeout,
+		},
+		{
+			scenario: "skipped",
+			context: func() (context.Context, context.CancelFunc) {
+				return SkipTimeout(context.Background()), func() {}
+			},
+			timeout: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := tc.context()
+			defer cancel()
+
+			start := time.Now()
+
+			newCtx, cancelNewCtx := newConfig(WithMaxTimeout(maxTimeout)).withTimeout(ctx, tc.timeout)
+			defer cancelNewCtx()
+
+			deadline, ok := newCtx.Deadline()
+
+			if tc.expectedDeadline == 0 {
+				assert.False(t, ok)
+
+				return
+			}
+
+			assert.True(t, ok)
+			assert.WithinDuration(t, start.Add(tc.expectedDeadline), deadline, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestWithClientTimeout_Budget(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		context          func() (context.Context, context.CancelFunc)
+		options          []Option
+		expectedDeadline time.Duration
+		expectedError    string
+	}{
+		{
+			scenario: "no deadline",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			options: []Option{WithReservedDuration(time.Second), WithMinBudget(time.Minute)},
+		},
+		{
+			scenario: "no reservation",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			expectedDeadline: 10 * time.Second,
+		},
+		{
+			scenario: "reserved duration",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedDuration(2 * time.Second)},
+			expectedDeadline: 8 * time.Second,
+		},
+		{
+			scenario: "reserved ratio",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedRatio(0.2)},
+			expectedDeadline: 8 * time.Second,
+		},
+		{
+			scenario: "reserved duration and ratio",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedDuration(time.Second), WithReservedRatio(0.1)},
+			expectedDeadline: 8 * time.Second,
+		},
+		{
+			scenario: "ratio of 1 is ignored",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedRatio(1)},
+			expectedDeadline: 10 * time.Second,
+		},
+		{
+			scenario: "ratio above 1 is ignored",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedRatio(1.5)},
+			expectedDeadline: 10 * time.Second,
+		},
+		{
+			scenario: "negative ratio is ignored",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:          []Option{WithReservedRatio(-0.5)},
+			expectedDeadline: 10 * time.Second,
+		},
+		{
+			scenario: "budget is below the minimum",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Second)
+			},
+			options:       []Option{WithReservedDuration(8 * time.Second), WithMinBudget(5 * time.Second)},
+			expectedError: `rpc error: code = DeadlineExceeded desc = deadline budget`,
+		},
+		{
+			scenario: "reservation exceeds the deadline",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			options:       []Option{WithReservedDuration(2 * time.Second)},
+			expectedError: `rpc error: code = DeadlineExceeded desc = deadline budget`,
+		},
+		{
+			scenario: "skipped",
+			context: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+
+				return SkipTimeout(ctx), cancel
+			},
+			options:          []Option{WithReservedDuration(2 * time.Second)},
+			expectedDeadline: time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := tc.context()
+			defer cancel()
+
+			start := time.Now()
+
+			newCtx, cancelNewCtx, err := newConfig(tc.options...).withClientTimeout(ctx, 0)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			defer cancelNewCtx()
+
+			deadline, ok := newCtx.Deadline()
+
+			if tc.expectedDeadline == 0 {
+				assert.False(t, ok)
+
+				return
+			}
+
+			assert.True(t, ok)
+			assert.WithinDuration(t, start.Add(tc.expectedDeadline), deadline, 100*time.Millisecond)
+		})
+	}
+}
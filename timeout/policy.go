@@ -0,0 +1,91 @@
+package timeout
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// PolicyOption configures a Policy.
+type PolicyOption func(p *Policy)
+
+// Policy maps gRPC methods to timeouts.
+//
+// The timeout of a method is resolved in this order:
+//   - the timeout of the full method name, e.g. "/pkg.Service/Method".
+//   - the timeout of the first pattern that matches the full method name, in the order they are added.
+//   - the timeout of the service, e.g. "pkg.Service".
+//   - the fallback timeout.
+type Policy struct {
+	fallback time.Duration
+	methods  map[string]time.Duration
+	services map[string]time.Duration
+	patterns []patternTimeout
+}
+
+type patternTimeout struct {
+	pattern string
+	timeout time.Duration
+}
+
+// NewPolicy creates a new timeout policy. The fallback timeout is used when there is no rule for the method.
+func NewPolicy(fallback time.Duration, opts ...PolicyOption) *Policy {
+	p := &Policy{
+		fallback: fallback,
+		methods:  make(map[string]time.Duration),
+		services: make(map[string]time.Duration),
+	}
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	return p
+}
+
+// Timeout returns the timeout for the full method name, e.g. "/pkg.Service/Method".
+func (p *Policy) Timeout(fullMethod string) time.Duration {
+	if d, ok := p.methods[fullMethod]; ok {
+		return d
+	}
+
+	for _, pt := range p.patterns {
+		if ok, err := path.Match(pt.pattern, fullMethod); err == nil && ok {
+			return pt.timeout
+		}
+	}
+
+	if d, ok := p.services[serviceName(fullMethod)]; ok {
+		return d
+	}
+
+	return p.fallback
+}
+
+// WithMethodTimeout sets the timeout for a full method name, e.g. "/pkg.Service/Method".
+func WithMethodTimeout(fullMethod string, timeout time.Duration) PolicyOption {
+	return func(p *Policy) {
+		p.methods[fullMethod] = timeout
+	}
+}
+
+// WithServiceTimeout sets the timeout for all the methods of a service, e.g. "pkg.Service".
+func WithServiceTimeout(service string, timeout time.Duration) PolicyOption {
+	return func(p *Policy) {
+		p.services[service] = timeout
+	}
+}
+
+// WithPatternTimeout sets the timeout for all the methods whose full name matches the glob pattern, e.g.
+// "/pkg.Service/*". See path.Match for the pattern syntax. A malformed pattern never matches.
+func WithPatternTimeout(pattern string, timeout time.Duration) PolicyOption {
+	return func(p *Policy) {
+		p.patterns = append(p.patterns, patternTimeout{pattern: pattern, timeout: timeout})
+	}
+}
+
+func serviceName(fullMethod string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return service
+}
@@ -0,0 +1,45 @@
+package timeout_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nhatthm/go-grpc-middleware/timeout"
+)
+
+func TestPolicy_Timeout(t *testing.T) {
+	t.Parallel()
+
+	p := timeout.NewPolicy(time.Second,
+		timeout.WithMethodTimeout("/pkg.Search/Search", 2*time.Second),
+		timeout.WithPatternTimeout("/pkg.Export/Export*", 5*time.Minute),
+		timeout.WithPatternTimeout("/pkg.Export/*", time.Minute),
+		timeout.WithPatternTimeout("/pkg.Broken/[", time.Hour),
+		timeout.WithServiceTimeout("pkg.Export", 3*time.Second),
+		timeout.WithServiceTimeout("pkg.Search", 4*time.Second),
+		timeout.WithServiceTimeout("pkg.Broken", 5*time.Second),
+	)
+
+	testCases := []struct {
+		method   string
+		expected time.Duration
+	}{
+		{method: "/pkg.Search/Search", expected: 2 * time.Second},
+		{method: "/pkg.Search/Suggest", expected: 4 * time.Second},
+		{method: "/pkg.Export/ExportAll", expected: 5 * time.Minute},
+		{method: "/pkg.Export/Status", expected: time.Minute},
+		{method: "/pkg.Broken/Method", expected: 5 * time.Second},
+		{method: "/pkg.Unknown/Method", expected: time.Second},
+		{method: "malformed", expected: time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, p.Timeout(tc.method))
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package timeout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidReloadInterval indicates that the reload interval of a FileProvider is not positive.
+var ErrInvalidReloadInterval = errors.New("invalid reload interval")
+
+var _ Provider = (*Policy)(nil)
+
+// Provider provides the timeout of a method. The interceptors consult the provider for every call.
+type Provider interface {
+	// Timeout returns the timeout for the full method name, e.g. "/pkg.Service/Method".
+	Timeout(fullMethod string) time.Duration
+}
+
+// AtomicProvider is an in-memory provider whose policy can be replaced at any time.
+type AtomicProvider struct {
+	policy atomic.Pointer[Policy]
+}
+
+var _ Provider = (*AtomicProvider)(nil)
+
+// NewAtomicProvider creates a new in-memory provider with an initial policy.
+func NewAtomicProvider(policy *Policy) *AtomicProvider {
+	p := &AtomicProvider{}
+
+	p.Store(policy)
+
+	return p
+}
+
+// Store replaces the policy. The new policy applies to the next calls.
+func (p *AtomicProvider) Store(policy *Policy) {
+	p.policy.Store(policy)
+}
+
+// Load returns the current policy.
+func (p *AtomicProvider) Load() *Policy {
+	return p.policy.Load()
+}
+
+// Timeout returns the timeout of the method in the current policy, or 0 if there is no policy.
+func (p *AtomicProvider) Timeout(fullMethod string) time.Duration {
+	policy := p.policy.Load()
+	if policy == nil {
+		return 0
+	}
+
+	return policy.Timeout(fullMethod)
+}
+
+// FileProviderOption configures a FileProvider.
+type FileProviderOption func(p *FileProvider)
+
+// FileProvider is a provider that loads the policy from a gRPC service config file and reloads it when the file
+// changes. See PolicyFromServiceConfig for the format of the file.
+type FileProvider struct {
+	AtomicProvider
+
+	path     string
+	interval time.Duration
+	onError  func(err error)
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ Provider = (*FileProvider)(nil)
+
+// NewFileProvider loads the policy from the gRPC service config file and starts watching the file for changes. If the
+// file could not be reloaded, the last policy is kept. Call Close to stop watching.
+func NewFileProvider(path string, opts ...FileProviderOption) (*FileProvider, error) {
+	p := &FileProvider{
+		path:     path,
+		interval: 10 * time.Second,
+		onError: func(error) {
+			// Keep the last policy.
+		},
+		done: make(chan struct{}),
+	}
+
+	for _, o := range opts {
+		o(p)
+	}
+
+	if p.interval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReloadInterval, p.interval)
+	}
+
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+
+	p.wg.Add(1)
+
+	go p.watch()
+
+	return p, nil
+}
+
+// WithReloadInterval sets how often the file is checked for changes, it must be positive. The default is 10 seconds.
+func WithReloadInterval(interval time.Duration) FileProviderOption {
+	return func(p *FileProvider) {
+		p.interval = interval
+	}
+}
+
+// WithReloadErrorHandler sets the function that is called when the file could not be reloaded.
+func WithReloadErrorHandler(f func(err error)) FileProviderOption {
+	return func(p *FileProvider) {
+		p.onError = f
+	}
+}
+
+// Reload loads the policy from the file.
+func (p *FileProvider) Reload() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.reload()
+}
+
+func (p *FileProvider) reload() error {
+	fi, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+
+	policy, err := PolicyFromServiceConfigFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	p.modTime, p.size = fi.ModTime(), fi.Size()
+
+	p.Store(policy)
+
+	return nil
+}
+
+// Close stops watching the file.
+func (p *FileProvider) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+
+	p.wg.Wait()
+
+	return nil
+}
+
+func (p *FileProvider) watch() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+
+		case <-ticker.C:
+			if err := p.reloadIfChanged(); err != nil {
+				p.onError(err)
+			}
+		}
+	}
+}
+
+func (p *FileProvider) reloadIfChanged() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fi, err := os.Stat(p.path)
+	if err != nil {
+		return err
+	}
+
+	if fi.ModTime().Equal(p.modTime) && fi.Size() == p.size {
+		return nil
+	}
+
+	return p.reload()
+}
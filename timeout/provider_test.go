@@ -0,0 +1,142 @@
+package timeout_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/nhatthm/go-grpc-middleware/timeout"
+)
+
+func TestAtomicProvider(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAtomicProvider(nil)
+
+	assert.Nil(t, p.Load())
+	assert.Equal(t, time.Duration(0), p.Timeout(method))
+
+	policy := timeout.NewPolicy(time.Second)
+
+	p.Store(policy)
+
+	assert.Same(t, policy, p.Load())
+	assert.Equal(t, time.Second, p.Timeout(method))
+}
+
+func TestUnaryClientTimeoutProviderInterceptor_ProviderChanges(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	p := timeout.NewAtomicProvider(timeout.NewPolicy(time.Second))
+	interceptor := timeout.UnaryClientTimeoutProviderInterceptor(p)
+
+	invoke := func(expected time.Duration) {
+		start := time.Now()
+
+		err := interceptor(context.Background(), method, nil, nil, nil,
+			func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				assertDeadline(t, ctx, start, expected)
+
+				return nil
+			},
+		)
+
+		require.NoError(t, err)
+	}
+
+	invoke(time.Second)
+
+	p.Store(timeout.NewPolicy(time.Second, timeout.WithMethodTimeout(method, time.Minute)))
+
+	invoke(time.Minute)
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	const method = "/pkg.Service/Method"
+
+	path := filepath.Join(t.TempDir(), "service_config.json")
+
+	writeServiceConfig(t, path, `{"methodConfig": [{"name": [{}], "timeout": "1s"}]}`)
+
+	errs := make(chan error, 10)
+
+	p, err := timeout.NewFileProvider(path,
+		timeout.WithReloadInterval(10*time.Millisecond),
+		timeout.WithReloadErrorHandler(func(err error) {
+			errs <- err
+		}),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, p.Close())
+	})
+
+	assert.Equal(t, time.Second, p.Timeout(method))
+
+	writeServiceConfig(t, path, `{"methodConfig": [{"name": [{"service": "pkg.Service"}], "timeout": "60s"}]}`)
+
+	assert.Eventually(t, func() bool {
+		return p.Timeout(method) == time.Minute
+	}, time.Second, 10*time.Millisecond)
+
+	// The last policy is kept when the file is broken.
+	writeServiceConfig(t, path, `{"methodConfig": [`)
+
+	select {
+	case err := <-errs:
+		require.ErrorIs(t, err, timeout.ErrInvalidServiceConfig)
+
+	case <-time.After(time.Second):
+		t.Fatal("reload error is expected")
+	}
+
+	assert.Equal(t, time.Minute, p.Timeout(method))
+}
+
+func TestNewFileProvider_Error(t *testing.T) {
+	t.Parallel()
+
+	p, err := timeout.NewFileProvider(filepath.Join(t.TempDir(), "unknown.json"))
+
+	assert.Nil(t, p)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewFileProvider_InvalidReloadInterval(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "service_config.json")
+
+	writeServiceConfig(t, path, `{}`)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		p, err := timeout.NewFileProvider(path, timeout.WithReloadInterval(interval))
+
+		assert.Nil(t, p)
+		require.ErrorIs(t, err, timeout.ErrInvalidReloadInterval)
+	}
+}
+
+func writeServiceConfig(t *testing.T, path, config string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	// Make sure the modification time changes even on file systems with a coarse resolution.
+	modTime := time.Now().Add(time.Duration(len(config)) * time.Second)
+
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
@@ -0,0 +1,121 @@
+package timeout
+
+import "sort"
+
+// quantileEstimator estimates a quantile of a stream of observations without storing them, using the P² algorithm.
+// See "The P² algorithm for dynamic calculation of quantiles and histograms without storing observations" by R. Jain
+// and I. Chlamtac.
+type quantileEstimator struct {
+	quantile float64
+	count    int
+
+	heights   [5]float64
+	positions [5]float64
+	desired   [5]float64
+	increment [5]float64
+}
+
+func newQuantileEstimator(quantile float64) *quantileEstimator {
+	return &quantileEstimator{
+		quantile:  quantile,
+		increment: [5]float64{0, quantile / 2, quantile, (1 + quantile) / 2, 1},
+	}
+}
+
+// Count returns the number of observations.
+func (e *quantileEstimator) Count() int {
+	return e.count
+}
+
+// Add adds an observation.
+func (e *quantileEstimator) Add(x float64) {
+	if e.count < len(e.heights) {
+		e.heights[e.count] = x
+		e.count++
+
+		if e.count == len(e.heights) {
+			sort.Float64s(e.heights[:])
+
+			q := e.quantile
+			e.positions = [5]float64{1, 2, 3, 4, 5}
+			e.desired = [5]float64{1, 1 + 2*q, 1 + 4*q, 3 + 2*q, 5}
+		}
+
+		return
+	}
+
+	e.count++
+
+	var k int
+
+	switch {
+	case x < e.heights[0]:
+		e.heights[0] = x
+		k = 0
+
+	case x >= e.heights[4]:
+		e.heights[4] = x
+		k = 3
+
+	default:
+		for k < 3 && x >= e.heights[k+1] {
+			k++
+		}
+	}
+
+	for i := k + 1; i < len(e.positions); i++ {
+		e.positions[i]++
+	}
+
+	for i := range e.desired {
+		e.desired[i] += e.increment[i]
+	}
+
+	for i := 1; i < 4; i++ {
+		d := e.desired[i] - e.positions[i]
+
+		if (d >= 1 && e.positions[i+1]-e.positions[i] > 1) || (d <= -1 && e.positions[i-1]-e.positions[i] < -1) {
+			sign := 1.0
+			if d < 0 {
+				sign = -1
+			}
+
+			h := e.parabolic(i, sign)
+			if e.heights[i-1] >= h || h >= e.heights[i+1] {
+				h = e.linear(i, sign)
+			}
+
+			e.heights[i] = h
+			e.positions[i] += sign
+		}
+	}
+}
+
+// Value returns the estimated quantile, or 0 if there is no observation.
+func (e *quantileEstimator) Value() float64 {
+	if e.count == 0 {
+		return 0
+	}
+
+	if e.count < len(e.heights) {
+		observed := make([]float64, e.count)
+		copy(observed, e.heights[:e.count])
+		sort.Float64s(observed)
+
+		return observed[int(e.quantile*float64(e.count-1)+0.5)]
+	}
+
+	return e.heights[2]
+}
+
+func (e *quantileEstimator) parabolic(i int, d float64) float64 {
+	n, h := e.positions, e.heights
+
+	return h[i] + d/(n[i+1]-n[i-1])*((n[i]-n[i-1]+d)*(h[i+1]-h[i])/(n[i+1]-n[i])+(n[i+1]-n[i]-d)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+}
+
+func (e *quantileEstimator) linear(i int, d float64) float64 {
+	j := i + int(d)
+
+	return e.heights[i] + d*(e.heights[j]-e.heights[i])/(e.positions[j]-e.positions[i])
+}
@@ -0,0 +1,48 @@
+package timeout
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantileEstimator_FewObservations(t *testing.T) {
+	t.Parallel()
+
+	e := newQuantileEstimator(0.5)
+
+	assert.Equal(t, 0, e.Count())
+	assert.InDelta(t, 0, e.Value(), 0)
+
+	e.Add(3)
+	e.Add(1)
+	e.Add(2)
+
+	assert.Equal(t, 3, e.Count())
+	assert.InDelta(t, 2, e.Value(), 0)
+}
+
+func TestQuantileEstimator_Uniform(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		quantile float64
+	}{
+		{quantile: 0.5},
+		{quantile: 0.9},
+		{quantile: 0.99},
+	}
+
+	for _, tc := range testCases {
+		e := newQuantileEstimator(tc.quantile)
+		r := rand.New(rand.NewPCG(1, 2)) //nolint: gosec
+
+		for range 100_000 {
+			e.Add(r.Float64() * 1000)
+		}
+
+		assert.Equal(t, 100_000, e.Count())
+		assert.InDelta(t, tc.quantile*1000, e.Value(), 10)
+	}
+}
@@ -0,0 +1,134 @@
+package timeout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidServiceConfig indicates that the service config is invalid.
+var ErrInvalidServiceConfig = errors.New("invalid service config")
+
+type serviceConfig struct {
+	MethodConfig []methodConfig `json:"methodConfig"`
+}
+
+type methodConfig struct {
+	Name    []methodName `json:"name"`
+	Timeout *string      `json:"timeout"`
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+// PolicyFromServiceConfig creates a timeout policy from the "methodConfig[].timeout" section of a gRPC service config
+// JSON. See https://github.com/grpc/grpc/blob/master/doc/service_config.md.
+//
+// A name with a service and a method sets the timeout of the method, a name with only a service sets the timeout of
+// all the methods of the service, and an empty name sets the fallback timeout. The method configs without timeout are
+// ignored.
+func PolicyFromServiceConfig(data []byte) (*Policy, error) {
+	var cfg serviceConfig
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidServiceConfig, err)
+	}
+
+	var (
+		fallback time.Duration
+		opts     []PolicyOption
+	)
+
+	seen := make(map[methodName]struct{})
+
+	for _, mc := range cfg.MethodConfig {
+		timeout, err := parseServiceConfigTimeout(mc.Timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, n := range mc.Name {
+			if _, ok := seen[n]; ok {
+				return nil, fmt.Errorf("%w: duplicate name %+v", ErrInvalidServiceConfig, n)
+			}
+
+			seen[n] = struct{}{}
+
+			if mc.Timeout == nil {
+				continue
+			}
+
+			switch {
+			case n.Service == "" && n.Method != "":
+				return nil, fmt.Errorf("%w: method %q without service", ErrInvalidServiceConfig, n.Method)
+
+			case n.Service == "":
+				fallback = timeout
+
+			case n.Method == "":
+				opts = append(opts, WithServiceTimeout(n.Service, timeout))
+
+			default:
+				opts = append(opts, WithMethodTimeout("/"+n.Service+"/"+n.Method, timeout))
+			}
+		}
+	}
+
+	return NewPolicy(fallback, opts...), nil
+}
+
+// PolicyFromServiceConfigFile creates a timeout policy from a gRPC service config JSON file.
+// See PolicyFromServiceConfig.
+func PolicyFromServiceConfigFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path) //nolint: gosec
+	if err != nil {
+		return nil, fmt.Errorf("could not read service config: %w", err)
+	}
+
+	return PolicyFromServiceConfig(data)
+}
+
+// maxDurationSeconds is the maximum number of seconds of a google.protobuf.Duration, about 10,000 years.
+const maxDurationSeconds = 315576000000
+
+// durationPattern matches a non-negative google.protobuf.Duration in JSON format, e.g. "1.5s".
+var durationPattern = regexp.MustCompile(`^([0-9]+)(?:\.([0-9]{1,9}))?s$`)
+
+// parseServiceConfigTimeout parses a google.protobuf.Duration in JSON format, e.g. "1.5s". The durations that do not
+// fit in a time.Duration, i.e. above about 292 years, are capped.
+func parseServiceConfigTimeout(s *string) (time.Duration, error) {
+	if s == nil {
+		return 0, nil
+	}
+
+	m := durationPattern.FindStringSubmatch(*s)
+	if m == nil {
+		return 0, fmt.Errorf("%w: malformed timeout %q", ErrInvalidServiceConfig, *s)
+	}
+
+	seconds, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || seconds > maxDurationSeconds {
+		return 0, fmt.Errorf("%w: timeout %q is out of range", ErrInvalidServiceConfig, *s)
+	}
+
+	var nanos int64
+
+	if m[2] != "" {
+		// The fraction has at most 9 digits, it always fits.
+		nanos, _ = strconv.ParseInt(m[2]+strings.Repeat("0", 9-len(m[2])), 10, 64) //nolint: errcheck
+	}
+
+	if seconds > (math.MaxInt64-nanos)/int64(time.Second) {
+		return time.Duration(math.MaxInt64), nil
+	}
+
+	return time.Duration(seconds)*time.Second + time.Duration(nanos), nil
+}
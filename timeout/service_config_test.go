@@ -0,0 +1,184 @@
+package timeout_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nhatthm/go-grpc-middleware/timeout"
+)
+
+const serviceConfig = `{
+	"loadBalancingConfig": [{"round_robin": {}}],
+	"methodConfig": [
+		{
+			"name": [{}],
+			"timeout": "1s"
+		},
+		{
+			"name": [{"service": "pkg.Search"}],
+			"timeout": "2.5s"
+		},
+		{
+			"name": [{"service": "pkg.Export", "method": "Export"}, {"service": "pkg.Export", "method": "Import"}],
+			"timeout": "300s"
+		},
+		{
+			"name": [{"service": "pkg.Retry"}],
+			"retryPolicy": {"maxAttempts": 3}
+		}
+	]
+}`
+
+func TestPolicyFromServiceConfig(t *testing.T) {
+	t.Parallel()
+
+	p, err := timeout.PolicyFromServiceConfig([]byte(serviceConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, p.Timeout("/pkg.Other/Method"))
+	assert.Equal(t, 2500*time.Millisecond, p.Timeout("/pkg.Search/Search"))
+	assert.Equal(t, 5*time.Minute, p.Timeout("/pkg.Export/Export"))
+	assert.Equal(t, 5*time.Minute, p.Timeout("/pkg.Export/Import"))
+	assert.Equal(t, time.Second, p.Timeout("/pkg.Export/Status"))
+	assert.Equal(t, time.Second, p.Timeout("/pkg.Retry/Method"))
+}
+
+func TestPolicyFromServiceConfig_NoDefault(t *testing.T) {
+	t.Parallel()
+
+	p, err := timeout.PolicyFromServiceConfig([]byte(`{"methodConfig": [{"name": [{"service": "pkg.Search"}], "timeout": "2s"}]}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Second, p.Timeout("/pkg.Search/Search"))
+	assert.Equal(t, time.Duration(0), p.Timeout("/pkg.Other/Method"))
+}
+
+func TestPolicyFromServiceConfig_Timeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		timeout  string
+		expected time.Duration
+	}{
+		{timeout: "0s", expected: 0},
+		{timeout: "1s", expected: time.Second},
+		{timeout: "1.5s", expected: 1500 * time.Millisecond},
+		{timeout: "0.000000001s", expected: time.Nanosecond},
+		{timeout: "315576000000s", expected: time.Duration(math.MaxInt64)},
+		{timeout: "9223372036.854775807s", expected: time.Duration(math.MaxInt64)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.timeout, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := timeout.PolicyFromServiceConfig([]byte(`{"methodConfig": [{"name": [{}], "timeout": "` + tc.timeout + `"}]}`))
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, p.Timeout("/pkg.Service/Method"))
+		})
+	}
+}
+
+func TestPolicyFromServiceConfig_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario      string
+		config        string
+		expectedError string
+	}{
+		{
+			scenario:      "malformed json",
+			config:        `{`,
+			expectedError: "invalid service config: unexpected end of JSON input",
+		},
+		{
+			scenario:      "timeout without unit",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "1"}]}`,
+			expectedError: `invalid service config: malformed timeout "1"`,
+		},
+		{
+			scenario:      "timeout with wrong unit",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "1ms"}]}`,
+			expectedError: `invalid service config: malformed timeout "1ms"`,
+		},
+		{
+			scenario:      "negative timeout",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "-1s"}]}`,
+			expectedError: `invalid service config: malformed timeout "-1s"`,
+		},
+		{
+			scenario:      "hexadecimal timeout",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "0x10s"}]}`,
+			expectedError: `invalid service config: malformed timeout "0x10s"`,
+		},
+		{
+			scenario:      "exponent timeout",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "1e3s"}]}`,
+			expectedError: `invalid service config: malformed timeout "1e3s"`,
+		},
+		{
+			scenario:      "infinite timeout",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "Infs"}]}`,
+			expectedError: `invalid service config: malformed timeout "Infs"`,
+		},
+		{
+			scenario:      "too many fractional digits",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "1.0000000001s"}]}`,
+			expectedError: `invalid service config: malformed timeout "1.0000000001s"`,
+		},
+		{
+			scenario:      "timeout out of range",
+			config:        `{"methodConfig": [{"name": [{}], "timeout": "315576000001s"}]}`,
+			expectedError: `invalid service config: timeout "315576000001s" is out of range`,
+		},
+		{
+			scenario:      "method without service",
+			config:        `{"methodConfig": [{"name": [{"method": "Search"}], "timeout": "1s"}]}`,
+			expectedError: `invalid service config: method "Search" without service`,
+		},
+		{
+			scenario:      "duplicate name",
+			config:        `{"methodConfig": [{"name": [{"service": "pkg.Search"}], "timeout": "1s"}, {"name": [{"service": "pkg.Search"}], "timeout": "2s"}]}`,
+			expectedError: `invalid service config: duplicate name {Service:pkg.Search Method:}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := timeout.PolicyFromServiceConfig([]byte(tc.config))
+
+			assert.Nil(t, p)
+			require.ErrorIs(t, err, timeout.ErrInvalidServiceConfig)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestPolicyFromServiceConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "service_config.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(serviceConfig), 0o600))
+
+	p, err := timeout.PolicyFromServiceConfigFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Minute, p.Timeout("/pkg.Export/Export"))
+
+	p, err = timeout.PolicyFromServiceConfigFile(filepath.Join(t.TempDir(), "unknown.json"))
+
+	assert.Nil(t, p)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
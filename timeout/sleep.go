@@ -0,0 +1,70 @@
+package timeout
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// SleepOption configures the sleep interceptors.
+type SleepOption func(c *sleepConfig)
+
+type sleepConfig struct {
+	policy *Policy
+	jitter time.Duration
+	random func() float64
+}
+
+func newSleepConfig(duration time.Duration, opts ...SleepOption) sleepConfig {
+	c := sleepConfig{
+		policy: NewPolicy(duration),
+		random: rand.Float64, //nolint: gosec
+	}
+
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// WithSleepPolicy sets the duration of the sleep per method. The duration of the method in the policy is used instead
+// of the duration given to the interceptor.
+func WithSleepPolicy(policy *Policy) SleepOption {
+	return func(c *sleepConfig) {
+		c.policy = policy
+	}
+}
+
+// WithSleepJitter adds a random duration, up to the given jitter, to the sleep.
+func WithSleepJitter(jitter time.Duration) SleepOption {
+	return func(c *sleepConfig) {
+		c.jitter = jitter
+	}
+}
+
+// sleep sleeps for the duration of the method, or until the context ends.
+func (c sleepConfig) sleep(ctx context.Context, method string) error {
+	d := c.policy.Timeout(method)
+
+	if c.jitter > 0 {
+		d += time.Duration(c.random() * float64(c.jitter))
+	}
+
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+
+	case <-t.C:
+		return nil
+	}
+}
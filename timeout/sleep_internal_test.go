@@ -0,0 +1,23 @@
+package timeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSleepConfig_Jitter(t *testing.T) {
+	t.Parallel()
+
+	c := newSleepConfig(20*time.Millisecond, WithSleepJitter(40*time.Millisecond))
+	c.random = func() float64 {
+		return 0.5
+	}
+
+	start := time.Now()
+
+	assert.NoError(t, c.sleep(context.Background(), "/pkg.Service/Method"))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
@@ -0,0 +1,80 @@
+package timeout_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/nhatthm/go-grpc-middleware/timeout"
+)
+
+func TestUnaryClientSleepInterceptor_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+
+	err := timeout.UnaryClientSleepInterceptor(time.Hour)(ctx, "/pkg.Service/Method", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			t.Fatal("the request must not be sent")
+
+			return nil
+		},
+	)
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestUnaryServerSleepInterceptor(t *testing.T) {
+	t.Parallel()
+
+	policy := timeout.NewPolicy(time.Hour, timeout.WithMethodTimeout("/pkg.Service/Fast", 10*time.Millisecond))
+	interceptor := timeout.UnaryServerSleepInterceptor(0, timeout.WithSleepPolicy(policy))
+
+	handler := func(context.Context, any) (any, error) {
+		return 42, nil
+	}
+
+	start := time.Now()
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Fast"}, handler)
+	require.NoError(t, err)
+
+	assert.Equal(t, 42, resp)
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	resp, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/pkg.Service/Slow"}, handler)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestStreamServerSleepInterceptor(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := timeout.StreamServerSleepInterceptor(time.Hour)(nil, &serverStream{context: ctx}, &grpc.StreamServerInfo{FullMethod: "/pkg.Service/Method"},
+		func(any, grpc.ServerStream) error {
+			t.Fatal("the stream must not be handled")
+
+			return nil
+		},
+	)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
@@ -2,36 +2,164 @@ package timeout
 
 import (
 	"context"
+	"sync"
 	"time"
 
+	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 )
 
 // StreamClientTimeoutInterceptor automatically start a context with timeout if it is not set.
-func StreamClientTimeoutInterceptor(duration time.Duration) grpc.StreamClientInterceptor {
+//
+// The timeout covers the whole lifetime of the stream, not only its setup. The context is released when the stream
+// finishes, i.e. when RecvMsg returns an error (including io.EOF) or, for non server-streaming calls, when the response
+// is received. If the caller's context ends before that, the stream context ends with it.
+func StreamClientTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.StreamClientInterceptor {
+	return StreamClientTimeoutPolicyInterceptor(NewPolicy(duration), opts...)
+}
+
+// StreamClientTimeoutPolicyInterceptor automatically start a context with the timeout of the method in the policy if
+// it is not set. Like StreamClientTimeoutInterceptor, the timeout covers the whole lifetime of the stream.
+//
+// The timeout can be overridden for a single call with WithCallTimeout or ContextWithCallTimeout.
+func StreamClientTimeoutPolicyInterceptor(policy *Policy, opts ...Option) grpc.StreamClientInterceptor {
+	return StreamClientTimeoutProviderInterceptor(policy, opts...)
+}
+
+// StreamClientTimeoutProviderInterceptor automatically start a context with the timeout of the method given by the
+// provider if it is not set. The provider is consulted for every call, so changes apply to the existing connections.
+//
+// The timeout can be overridden for a single call with WithCallTimeout or ContextWithCallTimeout.
+func StreamClientTimeoutProviderInterceptor(provider Provider, opts ...Option) grpc.StreamClientInterceptor {
+	c := newConfig(opts...)
+
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-		ctx, cancel := withTimeout(ctx, duration)
-		defer cancel()
+		duration, opts, ok := callTimeout(ctx, opts)
+		if !ok {
+			duration = provider.Timeout(method)
+		}
 
-		return streamer(ctx, desc, cc, method, opts...)
+		ctx, cancel, err := c.withClientTimeout(ctx, duration)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+
+			return nil, err
+		}
+
+		return newTimeoutClientStream(s, desc, cancel), nil
 	}
 }
 
-// StreamClientSleepInterceptor sleeps for a moment before doing the job.
-func StreamClientSleepInterceptor(duration time.Duration) grpc.StreamClientInterceptor {
+// StreamClientSleepInterceptor sleeps for a moment before doing the job. The call fails as soon as the context ends.
+//
+// For chaos testing, see the faultinject package.
+func StreamClientSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.StreamClientInterceptor {
+	c := newSleepConfig(duration, opts...)
+
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-		time.Sleep(duration)
+		if err := c.sleep(ctx, method); err != nil {
+			return nil, err
+		}
 
 		return streamer(ctx, desc, cc, method, opts...)
 	}
 }
 
+// StreamServerSleepInterceptor sleeps for a moment before handling the stream. The call fails as soon as the context
+// ends.
+func StreamServerSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.StreamServerInterceptor {
+	c := newSleepConfig(duration, opts...)
+
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := c.sleep(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+// StreamServerTimeoutInterceptor automatically start a context with timeout if the caller did not set a deadline.
+//
+// With WithMaxTimeout, the deadlines, the default one and the one of the caller, are capped to the maximum timeout.
+func StreamServerTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts...)
+
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, cancel := c.withTimeout(stream.Context(), duration)
+		defer cancel()
+
+		wrapped := grpcMiddleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+
+		return handler(srv, wrapped)
+	}
+}
+
 // WithStreamClientTimeoutInterceptor appends StreamClientTimeoutInterceptor to dial option.
-func WithStreamClientTimeoutInterceptor(duration time.Duration) grpc.DialOption {
-	return grpc.WithChainStreamInterceptor(StreamClientTimeoutInterceptor(duration))
+func WithStreamClientTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamClientTimeoutInterceptor(duration, opts...))
+}
+
+// WithStreamClientTimeoutPolicyInterceptor appends StreamClientTimeoutPolicyInterceptor to dial option.
+func WithStreamClientTimeoutPolicyInterceptor(policy *Policy, opts ...Option) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamClientTimeoutPolicyInterceptor(policy, opts...))
+}
+
+// WithStreamClientTimeoutProviderInterceptor appends StreamClientTimeoutProviderInterceptor to dial option.
+func WithStreamClientTimeoutProviderInterceptor(provider Provider, opts ...Option) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamClientTimeoutProviderInterceptor(provider, opts...))
 }
 
 // WithStreamClientSleepInterceptor appends StreamClientSleepInterceptor to dial option.
-func WithStreamClientSleepInterceptor(duration time.Duration) grpc.DialOption {
-	return grpc.WithChainStreamInterceptor(StreamClientSleepInterceptor(duration))
+func WithStreamClientSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamClientSleepInterceptor(duration, opts...))
+}
+
+// WithStreamServerTimeoutInterceptor appends StreamServerTimeoutInterceptor to server option.
+func WithStreamServerTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(StreamServerTimeoutInterceptor(duration, opts...))
+}
+
+// WithStreamServerSleepInterceptor appends StreamServerSleepInterceptor to server option.
+func WithStreamServerSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.ServerOption {
+	return grpc.ChainStreamInterceptor(StreamServerSleepInterceptor(duration, opts...))
+}
+
+// timeoutClientStream owns the cancel function of the stream context and releases it when the stream finishes.
+type timeoutClientStream struct {
+	grpc.ClientStream
+
+	serverStreams bool
+	cancel        context.CancelFunc
+	cancelOnce    sync.Once
+}
+
+func newTimeoutClientStream(s grpc.ClientStream, desc *grpc.StreamDesc, cancel context.CancelFunc) *timeoutClientStream {
+	return &timeoutClientStream{
+		ClientStream:  s,
+		serverStreams: desc == nil || desc.ServerStreams,
+		cancel:        cancel,
+	}
+}
+
+func (s *timeoutClientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+
+	// The stream is finished when there is an error (io.EOF included) or when the only response of a non
+	// server-streaming call is received.
+	if err != nil || !s.serverStreams {
+		s.finish()
+	}
+
+	return err
+}
+
+func (s *timeoutClientStream) finish() {
+	s.cancelOnce.Do(s.cancel)
 }
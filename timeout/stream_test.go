@@ -2,6 +2,8 @@ package timeout_test
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net"
 	"testing"
 	"time"
@@ -9,8 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/nhatthm/go-grpc-middleware/timeout"
 )
@@ -41,3 +46,195 @@ func TestWithStreamClientInterceptors(t *testing.T) {
 	assert.Nil(t, s)
 	assert.EqualError(t, err, expected)
 }
+
+func TestStreamClientTimeoutInterceptor_StreamOutlivesInterceptor(t *testing.T) {
+	t.Parallel()
+
+	conn := newEchoStreamConn(t, timeout.WithStreamClientTimeoutInterceptor(time.Second))
+
+	s, err := conn.NewStream(context.Background(), echoStreamDesc, echoStreamMethod)
+	require.NoError(t, err)
+
+	// The interceptor returned, the stream must still be usable.
+	require.NoError(t, s.SendMsg(wrapperspb.String("hello")))
+
+	out := new(wrapperspb.StringValue)
+
+	require.NoError(t, s.RecvMsg(out))
+	assert.Equal(t, "hello", out.GetValue())
+	assert.NoError(t, s.Context().Err())
+
+	require.NoError(t, s.CloseSend())
+
+	// Drain the stream, the context is released afterward.
+	assert.ErrorIs(t, s.RecvMsg(out), io.EOF)
+	assert.ErrorIs(t, s.Context().Err(), context.Canceled)
+}
+
+func TestStreamClientTimeoutInterceptor_DeadlineCoversWholeStream(t *testing.T) {
+	t.Parallel()
+
+	duration := time.Millisecond * 50
+	conn := newEchoStreamConn(t, timeout.WithStreamClientTimeoutInterceptor(duration))
+
+	s, err := conn.NewStream(context.Background(), echoStreamDesc, echoStreamMethod)
+	require.NoError(t, err)
+
+	require.NoError(t, s.SendMsg(wrapperspb.String("hello")))
+	require.NoError(t, s.RecvMsg(new(wrapperspb.StringValue)))
+
+	time.Sleep(duration * 2)
+
+	err = s.RecvMsg(new(wrapperspb.StringValue))
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+const echoStreamMethod = "/grpctest.EchoService/Echo"
+
+var echoStreamDesc = &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}
+
+func newEchoStreamConn(t *testing.T, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+
+	buf := bufconn.Listen(1024 * 1024)
+
+	srv := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		for {
+			in := new(wrapperspb.StringValue)
+
+			if err := stream.RecvMsg(in); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+
+				return err
+			}
+
+			if err := stream.SendMsg(in); err != nil {
+				return err
+			}
+		}
+	}))
+
+	go func() {
+		_ = srv.Serve(buf) //nolint: errcheck
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return buf.Dial()
+		}),
+	}, opts...)
+
+	conn, err := grpc.NewClient("passthrough://", opts...)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close() //nolint: errcheck
+	})
+
+	return conn
+}
+
+func TestStreamServerTimeoutInterceptor(t *testing.T) {
+	t.Parallel()
+
+	duration := time.Hour
+
+	testCases := []struct {
+		scenario         string
+		context          func() (context.Context, context.CancelFunc)
+		options          []timeout.Option
+		expectedDeadline time.Duration
+	}{
+		{
+			scenario: "no deadline",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			expectedDeadline: duration,
+		},
+		{
+			scenario: "caller deadline is kept",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario: "timeout is skipped",
+			context: func() (context.Context, context.CancelFunc) {
+				return timeout.SkipTimeout(context.Background()), func() {}
+			},
+		},
+		{
+			scenario: "caller deadline is shortened",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 24*time.Hour)
+			},
+			options:          []timeout.Option{timeout.WithMaxTimeout(time.Minute)},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario: "default timeout is capped",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			options:          []timeout.Option{timeout.WithMaxTimeout(time.Minute)},
+			expectedDeadline: time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := tc.context()
+			defer cancel()
+
+			start := time.Now()
+			stream := &serverStream{context: ctx}
+
+			err := timeout.StreamServerTimeoutInterceptor(duration, tc.options...)(nil, stream, &grpc.StreamServerInfo{}, func(_ any, stream grpc.ServerStream) error {
+				assertDeadline(t, stream.Context(), start, tc.expectedDeadline)
+
+				return nil
+			})
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+
+	context context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.context
+}
+
+func TestStreamClientTimeoutPolicyInterceptor(t *testing.T) {
+	t.Parallel()
+
+	p := timeout.NewPolicy(time.Second,
+		timeout.WithMethodTimeout(echoStreamMethod, time.Minute),
+	)
+
+	conn := newEchoStreamConn(t, timeout.WithStreamClientTimeoutPolicyInterceptor(p))
+	start := time.Now()
+
+	s, err := conn.NewStream(context.Background(), echoStreamDesc, echoStreamMethod)
+	require.NoError(t, err)
+
+	assertDeadline(t, s.Context(), start, time.Minute)
+
+	require.NoError(t, s.CloseSend())
+	assert.ErrorIs(t, s.RecvMsg(new(wrapperspb.StringValue)), io.EOF)
+}
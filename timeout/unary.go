@@ -2,36 +2,150 @@ package timeout
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // UnaryClientTimeoutInterceptor automatically start a context with timeout if it is not set.
-func UnaryClientTimeoutInterceptor(duration time.Duration) grpc.UnaryClientInterceptor {
+func UnaryClientTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.UnaryClientInterceptor {
+	return UnaryClientTimeoutPolicyInterceptor(NewPolicy(duration), opts...)
+}
+
+// UnaryClientTimeoutPolicyInterceptor automatically start a context with the timeout of the method in the policy if it
+// is not set.
+//
+// The timeout can be overridden for a single call with WithCallTimeout or ContextWithCallTimeout.
+func UnaryClientTimeoutPolicyInterceptor(policy *Policy, opts ...Option) grpc.UnaryClientInterceptor {
+	return UnaryClientTimeoutProviderInterceptor(policy, opts...)
+}
+
+// UnaryClientTimeoutProviderInterceptor automatically start a context with the timeout of the method given by the
+// provider if it is not set. The provider is consulted for every call, so changes apply to the existing connections.
+// If the provider is also an Observer, the latency of the calls is reported to it.
+//
+// The timeout can be overridden for a single call with WithCallTimeout or ContextWithCallTimeout.
+func UnaryClientTimeoutProviderInterceptor(provider Provider, opts ...Option) grpc.UnaryClientInterceptor {
+	c := newConfig(opts...)
+	observer, _ := provider.(Observer) //nolint: errcheck
+
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		ctx, cancel := withTimeout(ctx, duration)
+		duration, opts, ok := callTimeout(ctx, opts)
+		if !ok {
+			duration = provider.Timeout(method)
+		}
+
+		callCtx, cancel, err := c.withClientTimeout(ctx, duration)
+		if err != nil {
+			return err
+		}
+
 		defer cancel()
 
-		return invoker(ctx, method, req, reply, cc, opts...)
+		if observer == nil {
+			return invoker(callCtx, method, req, reply, cc, opts...)
+		}
+
+		start := time.Now()
+		err = invoker(callCtx, method, req, reply, cc, opts...)
+
+		if latency, ok := observedLatency(ctx, callCtx, err, time.Since(start)); ok {
+			observer.Observe(method, latency)
+		}
+
+		return err
 	}
 }
 
-// UnaryClientSleepInterceptor automatically start a context with timeout if it is not set.
-func UnaryClientSleepInterceptor(duration time.Duration) grpc.UnaryClientInterceptor {
+// observedLatency returns the latency of a call to report to an Observer. The calls that are canceled, or that exceed
+// the deadline of the caller, are not reported. The calls that exceed the timeout of the interceptor are reported with
+// their elapsed time, a lower bound of their latency, so the timeout grows when it is too tight.
+func observedLatency(parent, callCtx context.Context, err error, elapsed time.Duration) (time.Duration, bool) {
+	switch status.Code(err) {
+	case codes.Canceled:
+		return 0, false
+
+	case codes.DeadlineExceeded:
+		if parent.Err() != nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return 0, false
+		}
+	}
+
+	return elapsed, true
+}
+
+// UnaryClientSleepInterceptor sleeps for a moment before doing the job. The call fails as soon as the context ends.
+//
+// For chaos testing, see the faultinject package.
+func UnaryClientSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.UnaryClientInterceptor {
+	c := newSleepConfig(duration, opts...)
+
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		time.Sleep(duration)
+		if err := c.sleep(ctx, method); err != nil {
+			return err
+		}
 
 		return invoker(ctx, method, req, reply, cc, opts...)
 	}
 }
 
+// UnaryServerSleepInterceptor sleeps for a moment before handling the call. The call fails as soon as the context
+// ends.
+func UnaryServerSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.UnaryServerInterceptor {
+	c := newSleepConfig(duration, opts...)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := c.sleep(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// UnaryServerTimeoutInterceptor automatically start a context with timeout if the caller did not set a deadline.
+//
+// With WithMaxTimeout, the deadlines, the default one and the one of the caller, are capped to the maximum timeout.
+func UnaryServerTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts...)
+
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := c.withTimeout(ctx, duration)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
+
 // WithUnaryClientTimeoutInterceptor appends UnaryClientTimeoutInterceptor to dial option.
-func WithUnaryClientTimeoutInterceptor(duration time.Duration) grpc.DialOption {
-	return grpc.WithChainUnaryInterceptor(UnaryClientTimeoutInterceptor(duration))
+func WithUnaryClientTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientTimeoutInterceptor(duration, opts...))
+}
+
+// WithUnaryClientTimeoutPolicyInterceptor appends UnaryClientTimeoutPolicyInterceptor to dial option.
+func WithUnaryClientTimeoutPolicyInterceptor(policy *Policy, opts ...Option) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientTimeoutPolicyInterceptor(policy, opts...))
+}
+
+// WithUnaryClientTimeoutProviderInterceptor appends UnaryClientTimeoutProviderInterceptor to dial option.
+func WithUnaryClientTimeoutProviderInterceptor(provider Provider, opts ...Option) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientTimeoutProviderInterceptor(provider, opts...))
 }
 
 // WithUnaryClientSleepInterceptor appends UnaryClientSleepInterceptor to dial option.
-func WithUnaryClientSleepInterceptor(duration time.Duration) grpc.DialOption {
-	return grpc.WithChainUnaryInterceptor(UnaryClientSleepInterceptor(duration))
+func WithUnaryClientSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryClientSleepInterceptor(duration, opts...))
+}
+
+// WithUnaryServerTimeoutInterceptor appends UnaryServerTimeoutInterceptor to server option.
+func WithUnaryServerTimeoutInterceptor(duration time.Duration, opts ...Option) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(UnaryServerTimeoutInterceptor(duration, opts...))
+}
+
+// WithUnaryServerSleepInterceptor appends UnaryServerSleepInterceptor to server option.
+func WithUnaryServerSleepInterceptor(duration time.Duration, opts ...SleepOption) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(UnaryServerSleepInterceptor(duration, opts...))
 }
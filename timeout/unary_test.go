@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 
 	"github.com/nhatthm/go-grpc-middleware/timeout"
@@ -48,3 +50,208 @@ func TestWithUnaryClientInterceptor(t *testing.T) {
 
 	assert.EqualError(t, err, expected)
 }
+
+func TestUnaryServerTimeoutInterceptor(t *testing.T) {
+	t.Parallel()
+
+	duration := time.Hour
+
+	testCases := []struct {
+		scenario         string
+		context          func() (context.Context, context.CancelFunc)
+		options          []timeout.Option
+		expectedDeadline time.Duration
+	}{
+		{
+			scenario: "no deadline",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			expectedDeadline: duration,
+		},
+		{
+			scenario: "caller deadline is kept",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Minute)
+			},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario: "timeout is skipped",
+			context: func() (context.Context, context.CancelFunc) {
+				return timeout.SkipTimeout(context.Background()), func() {}
+			},
+		},
+		{
+			scenario: "caller deadline is shortened",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 24*time.Hour)
+			},
+			options:          []timeout.Option{timeout.WithMaxTimeout(time.Minute)},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario: "default timeout is capped",
+			context: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			options:          []timeout.Option{timeout.WithMaxTimeout(time.Minute)},
+			expectedDeadline: time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := tc.context()
+			defer cancel()
+
+			start := time.Now()
+
+			resp, err := timeout.UnaryServerTimeoutInterceptor(duration, tc.options...)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+				assertDeadline(t, ctx, start, tc.expectedDeadline)
+
+				return 42, nil
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, 42, resp)
+		})
+	}
+}
+
+func assertDeadline(t *testing.T, ctx context.Context, start time.Time, expected time.Duration) {
+	t.Helper()
+
+	deadline, ok := ctx.Deadline()
+
+	if expected == 0 {
+		assert.False(t, ok, "no deadline is expected")
+
+		return
+	}
+
+	require.True(t, ok, "deadline is expected")
+	assert.WithinDuration(t, start.Add(expected), deadline, time.Second)
+}
+
+func TestUnaryClientTimeoutPolicyInterceptor(t *testing.T) {
+	t.Parallel()
+
+	p := timeout.NewPolicy(time.Second,
+		timeout.WithMethodTimeout("/pkg.Search/Search", 2*time.Second),
+		timeout.WithPatternTimeout("/pkg.Export/*", 5*time.Minute),
+	)
+
+	testCases := []struct {
+		method           string
+		expectedDeadline time.Duration
+	}{
+		{method: "/pkg.Search/Search", expectedDeadline: 2 * time.Second},
+		{method: "/pkg.Export/Export", expectedDeadline: 5 * time.Minute},
+		{method: "/pkg.Other/Method", expectedDeadline: time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Now()
+
+			err := timeout.UnaryClientTimeoutPolicyInterceptor(p)(context.Background(), tc.method, nil, nil, nil,
+				func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+					assertDeadline(t, ctx, start, tc.expectedDeadline)
+
+					return nil
+				},
+			)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUnaryClientTimeoutInterceptor_FailFastOnLowBudget(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	interceptor := timeout.UnaryClientTimeoutInterceptor(time.Minute,
+		timeout.WithReservedRatio(0.5),
+		timeout.WithMinBudget(time.Second),
+	)
+
+	err := interceptor(ctx, "/pkg.Service/Method", nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			t.Fatal("the request must not be sent")
+
+			return nil
+		},
+	)
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestUnaryClientTimeoutInterceptor_CallTimeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		scenario         string
+		context          context.Context
+		callOptions      []grpc.CallOption
+		expectedDeadline time.Duration
+	}{
+		{
+			scenario:         "default",
+			context:          context.Background(),
+			callOptions:      []grpc.CallOption{grpc.WaitForReady(true)},
+			expectedDeadline: time.Second,
+		},
+		{
+			scenario:         "call option",
+			context:          context.Background(),
+			callOptions:      []grpc.CallOption{timeout.WithCallTimeout(time.Minute), grpc.WaitForReady(true)},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario:         "last call option wins",
+			context:          context.Background(),
+			callOptions:      []grpc.CallOption{timeout.WithCallTimeout(time.Minute), grpc.WaitForReady(true), timeout.WithCallTimeout(time.Hour)},
+			expectedDeadline: time.Hour,
+		},
+		{
+			scenario:         "context",
+			context:          timeout.ContextWithCallTimeout(context.Background(), time.Minute),
+			callOptions:      []grpc.CallOption{grpc.WaitForReady(true)},
+			expectedDeadline: time.Minute,
+		},
+		{
+			scenario:         "call option takes precedence over context",
+			context:          timeout.ContextWithCallTimeout(context.Background(), time.Minute),
+			callOptions:      []grpc.CallOption{grpc.WaitForReady(true), timeout.WithCallTimeout(time.Hour)},
+			expectedDeadline: time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Now()
+
+			err := timeout.UnaryClientTimeoutInterceptor(time.Second)(tc.context, "/pkg.Service/Method", nil, nil, nil,
+				func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+					assertDeadline(t, ctx, start, tc.expectedDeadline)
+					assert.Equal(t, []grpc.CallOption{grpc.WaitForReady(true)}, opts)
+
+					return nil
+				},
+				tc.callOptions...,
+			)
+
+			require.NoError(t, err)
+		})
+	}
+}